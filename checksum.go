@@ -4,6 +4,9 @@ import (
 	"fmt"
 )
 
+// crcLength is the length of the hexadecimal CRC value closing a payload.
+const crcLength = 4
+
 // checksum calculates the checksum according to ISO/IEC 13239 with the specified polynomial and initial value.
 func checksum(data []byte) string {
 	// Polynomial '1021' in hexadecimal
@@ -28,8 +31,9 @@ func checksum(data []byte) string {
 	return fmt.Sprintf("%04X", _checksum)
 }
 
+// VerifyPayloadChecksum reports whether the last 4 characters of data are the CRC of the rest.
 func VerifyPayloadChecksum(data string) bool {
-	if data == "" {
+	if len(data) < crcLength {
 		return false
 	}
 	payload, crc := splitData(data)
@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"github.com/Jdemon/thaiqr"
 	"image"
@@ -11,6 +12,63 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "diff":
+			diff(os.Args[2:])
+			return
+		case "show":
+			show(os.Args[2:])
+			return
+		}
+	}
+
+	demo()
+}
+
+// diff prints the semantic differences between two payloads.
+func diff(args []string) {
+	if len(args) != 2 {
+		fmt.Println("usage: thaiqr diff <payload-a> <payload-b>")
+		os.Exit(2)
+	}
+
+	changes, err := thaiqr.Diff(args[0], args[1])
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	if len(changes) == 0 {
+		fmt.Println("no differences")
+		return
+	}
+	for _, change := range changes {
+		fmt.Println(change.String())
+	}
+}
+
+// show prints a payload as a QR code that can be scanned from the terminal.
+func show(args []string) {
+	flags := flag.NewFlagSet("show", flag.ExitOnError)
+	ansi := flags.Bool("ansi", false, "draw modules with ANSI colours instead of half blocks")
+	invert := flags.Bool("invert", false, "swap dark and light modules, for light text on a dark background")
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		fmt.Println("usage: thaiqr show [-ansi] [-invert] <payload>")
+		os.Exit(2)
+	}
+
+	to := thaiqr.TerminalOptions{Mode: thaiqr.TerminalModeHalfBlock, Invert: *invert}
+	if *ansi {
+		to.Mode = thaiqr.TerminalModeANSI
+	}
+	if err := thaiqr.WriteTerminal(os.Stdout, flags.Arg(0), to); err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+}
+
+func demo() {
 	payload := "003700060000010103006021620231130773524225102TH9104EC49"
 	qr := thaiqr.NewVerifyPaySlipQR()
 	data, err := qr.Reader(payload)
@@ -3,7 +3,10 @@ package thaiqr
 import (
 	"errors"
 	"fmt"
+	"github.com/Jdemon/thaiqr/tlv"
+	"math"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -21,15 +24,62 @@ func sanitizeTarget(value string) string {
 	return re.ReplaceAllString(value, "")
 }
 
-// serialize concatenates a slice of strings into a single string.
-func serialize(values []string) string {
-	return strings.Join(values, "")
+// payloadBuilder serializes data objects with tlv.AppendField. It keeps the first error, such as a
+// value longer than tlv.MaxLength, so a generator can write every field and check once at the end.
+type payloadBuilder struct {
+	buf []byte
+	err error
 }
 
-// f formats an ID and a value into a string.
-func formatField(id, value string) string {
-	ext := "00" + strconv.Itoa(len(value))
-	return id + ext[len(ext)-2:] + value
+// field appends a data object.
+func (b *payloadBuilder) field(id, value string) {
+	if b.err != nil {
+		return
+	}
+	if b.buf, b.err = tlv.AppendField(b.buf, id, value); b.err != nil {
+		b.err = fmt.Errorf("field %s: %w", id, b.err)
+	}
+}
+
+// template appends a data object whose value is the data objects written to nested.
+func (b *payloadBuilder) template(id string, nested *payloadBuilder) {
+	if b.err == nil {
+		b.err = nested.err
+	}
+	b.field(id, string(nested.buf))
+}
+
+// raw appends data objects that are already serialized, such as those returned by Scheme.EncodeTemplate.
+func (b *payloadBuilder) raw(data ...string) {
+	if b.err != nil {
+		return
+	}
+	for _, d := range data {
+		b.buf = append(b.buf, d...)
+	}
+}
+
+// empty reports whether no data object has been written.
+func (b *payloadBuilder) empty() bool {
+	return len(b.buf) == 0
+}
+
+// string returns the serialized data objects, or the first error met writing them.
+func (b *payloadBuilder) string() (string, error) {
+	if b.err != nil {
+		return "", b.err
+	}
+	return string(b.buf), nil
+}
+
+// withChecksum appends the CRC field id, calculated over the data objects written so far and the
+// CRC field's own ID and length, and returns the payload.
+func (b *payloadBuilder) withChecksum(id string) (string, error) {
+	if b.err != nil {
+		return "", b.err
+	}
+	b.field(id, checksum(append([]byte(string(b.buf)), id+"04"...)))
+	return b.string()
 }
 
 // formatTarget sanitizes and formats the target value.
@@ -48,12 +98,26 @@ func formatTarget(value string) string {
 
 // formatAmount converts the amount to a formatted string.
 func formatAmount(amount string) (string, error) {
-	if f, err := strconv.ParseFloat(amount, 32); err == nil {
+	if f, err := strconv.ParseFloat(amount, 64); err == nil {
 		return fmt.Sprintf("%.2f", f), nil
 	}
 	return "", errors.New("invalid amount")
 }
 
+// formatAmountWithExponent converts the amount to a string with the given number of minor unit digits,
+// rejecting amounts that cannot be represented, such as fractions of a zero-exponent currency.
+func formatAmountWithExponent(amount string, exponent int) (string, error) {
+	f, err := strconv.ParseFloat(amount, 64)
+	if err != nil || f < 0 {
+		return "", errors.New("invalid amount")
+	}
+	scale := math.Pow10(exponent)
+	if math.Abs(f*scale-math.Round(f*scale)) > 1e-6 {
+		return "", errors.New("invalid amount")
+	}
+	return strconv.FormatFloat(f, 'f', exponent, 64), nil
+}
+
 // ifThenElse returns 'a' if the condition is true, otherwise 'b'.
 func ifThenElse(condition bool, a, b interface{}) interface{} {
 	if condition {
@@ -68,62 +132,79 @@ func deserialize(data string) (map[string]string, []Segment, error) {
 	}
 
 	segments := make([]Segment, 0)
-
-	remain := data
 	results := make(map[string]string)
-	for remain != "" {
-		var value string
-		keyAndLen, payload, err := getAndRemove(remain, 4)
-		if err != nil {
-			return nil, nil, err
-		}
-		key, lengthStr, err := getAndRemove(keyAndLen, 2)
-		if err != nil {
-			return nil, nil, err
-		}
-		length, err := parseInt(lengthStr)
-		if err != nil {
-			return nil, nil, err
-		}
-		value, remain, err = getAndRemove(payload, length)
-		if err != nil {
-			return nil, nil, err
-		}
+	decoder := tlv.NewDecoder(data)
+	for decoder.Next() {
+		field := decoder.Field()
 		segments = append(segments, Segment{
-			RawValue: fmt.Sprintf("%s%s%s", key, lengthStr, value),
-			ID:       key,
-			Length:   length,
-			Value:    value,
+			RawValue: field.Raw,
+			ID:       field.ID,
+			Length:   field.Length,
+			Value:    field.Value,
 		})
-		results[key] = value
+		results[field.ID] = field.Value
+	}
+	if err := decoder.Err(); err != nil {
+		return nil, nil, err
 	}
 
 	return results, segments, nil
 }
 
-func getAndRemove(data string, length int) (string, string, error) {
-	if len(data) < length {
-		return "", "", invalidFormat()
+// minMerchantPresentedLength is the length of the shortest merchant presented payload: the payload
+// format indicator "000201" followed by the CRC field "6304XXXX".
+const minMerchantPresentedLength = 14
+
+// deserializeMerchantPresented verifies the checksum, payload format indicator and point of
+// initiation method of an EMV merchant presented payload and deserializes its top level fields.
+func deserializeMerchantPresented(data string) (map[string]string, []Segment, error) {
+	if len(data) < minMerchantPresentedLength {
+		return nil, nil, invalidFormat()
+	}
+
+	if !VerifyPayloadChecksum(data) {
+		return nil, nil, errors.New("invalid checksum")
 	}
-	key := data[:length]
-	data = data[length:]
-	return key, data, nil
-}
 
-func parseInt(s string) (int, error) {
-	val, err := strconv.Atoi(s)
+	fields, segments, err := deserialize(data)
 	if err != nil {
-		return 0, err
+		return nil, nil, err
+	}
+
+	if fields[IDPayloadFormat] != PayloadFormatEMVQRCPSMerchantPresentedMode {
+		return nil, nil, invalidFormat()
 	}
-	return val, nil
+	if !slices.Contains([]string{POIMethodStatic, POIMethodDynamic}, fields[IDPOIMethod]) {
+		return nil, nil, invalidFormat()
+	}
+
+	return fields, segments, nil
 }
 
+// findMerchantAccount returns the merchant account template (tags 26-51) whose globally unique identifier matches guid.
+func findMerchantAccount(segments []Segment, guid string) (string, map[string]string, []Segment, bool) {
+	for _, segment := range segments {
+		if !isMerchantAccountID(segment.ID) {
+			continue
+		}
+		fields, subSegments, err := deserialize(segment.Value)
+		if err == nil && strings.EqualFold(fields["00"], guid) {
+			return segment.ID, fields, subSegments, true
+		}
+	}
+	return "", nil, nil, false
+}
+
+// splitData splits data into the payload and its trailing CRC value. Data shorter than a CRC is all payload.
 func splitData(data string) (string, string) {
-	splitIndex := len(data) - 4
+	splitIndex := max(len(data)-crcLength, 0)
 	return data[:splitIndex], data[splitIndex:]
-
 }
 
 func invalidFormat() error {
 	return errors.New("invalid format")
 }
+
+func unsupportedFormat() error {
+	return errors.New("unsupported format")
+}
@@ -0,0 +1,264 @@
+// Package cpm reads and writes EMV consumer presented mode (CPM) QR codes, the wallet QR a
+// customer shows at the POS for B-scan-C payments. The payload is base64 encoded BER-TLV.
+package cpm
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+const (
+	TagPayloadFormatIndicator      = "85"
+	TagApplicationTemplate         = "61"
+	TagCommonDataTemplate          = "62"
+	TagApplicationSpecificTemplate = "63"
+	TagAID                         = "4F"
+	TagApplicationLabel            = "50"
+	TagTrack2EquivalentData        = "57"
+	TagApplicationPAN              = "5A"
+	TagCardholderName              = "5F20"
+	TagLanguagePreference          = "5F2D"
+	TagIssuerURL                   = "5F50"
+	TagApplicationVersionNumber    = "9F08"
+	TagTokenRequestorID            = "9F19"
+	TagPaymentAccountReference     = "9F24"
+	TagLast4DigitsOfPAN            = "9F25"
+
+	PayloadFormatIndicatorCPV01 = "CPV01"
+)
+
+const (
+	track2Separator = "D"
+	bcdPadding      = "F"
+)
+
+// Payload is a decoded consumer presented mode QR code.
+type Payload struct {
+	PayloadFormatIndicator string        `json:"payloadFormatIndicator"`
+	Applications           []Application `json:"applications"`
+	CommonData             *Application  `json:"commonData,omitempty"`
+}
+
+// Application holds the data objects of an application template (tag 61) or the common
+// data template (tag 62). Binary objects are upper case hex, BCD objects their digits.
+type Application struct {
+	AID                      string `json:"aid,omitempty"`
+	ApplicationLabel         string `json:"applicationLabel,omitempty"`
+	Track2EquivalentData     string `json:"track2EquivalentData,omitempty"`
+	ApplicationPAN           string `json:"applicationPan,omitempty"`
+	CardholderName           string `json:"cardholderName,omitempty"`
+	LanguagePreference       string `json:"languagePreference,omitempty"`
+	IssuerURL                string `json:"issuerUrl,omitempty"`
+	ApplicationVersionNumber string `json:"applicationVersionNumber,omitempty"`
+	TokenRequestorID         string `json:"tokenRequestorId,omitempty"`
+	PaymentAccountReference  string `json:"paymentAccountReference,omitempty"`
+	Last4DigitsOfPAN         string `json:"last4DigitsOfPan,omitempty"`
+	// ApplicationSpecific holds the application specific transparent template (tag 63),
+	// e.g. the cryptogram and ATC.
+	ApplicationSpecific []TLV `json:"applicationSpecific,omitempty"`
+	// Extra holds data objects of the template not mapped to a field above.
+	Extra []TLV `json:"extra,omitempty"`
+}
+
+type valueFormat int
+
+const (
+	formatASCII valueFormat = iota
+	formatBinary
+	formatBCD
+)
+
+var applicationFields = []struct {
+	tag    string
+	format valueFormat
+	field  func(*Application) *string
+}{
+	{TagAID, formatBinary, func(a *Application) *string { return &a.AID }},
+	{TagApplicationLabel, formatASCII, func(a *Application) *string { return &a.ApplicationLabel }},
+	{TagTrack2EquivalentData, formatBCD, func(a *Application) *string { return &a.Track2EquivalentData }},
+	{TagApplicationPAN, formatBCD, func(a *Application) *string { return &a.ApplicationPAN }},
+	{TagCardholderName, formatASCII, func(a *Application) *string { return &a.CardholderName }},
+	{TagLanguagePreference, formatASCII, func(a *Application) *string { return &a.LanguagePreference }},
+	{TagIssuerURL, formatASCII, func(a *Application) *string { return &a.IssuerURL }},
+	{TagApplicationVersionNumber, formatBinary, func(a *Application) *string { return &a.ApplicationVersionNumber }},
+	{TagTokenRequestorID, formatBinary, func(a *Application) *string { return &a.TokenRequestorID }},
+	{TagPaymentAccountReference, formatASCII, func(a *Application) *string { return &a.PaymentAccountReference }},
+	{TagLast4DigitsOfPAN, formatBCD, func(a *Application) *string { return &a.Last4DigitsOfPAN }},
+}
+
+// QR represents an EMV consumer presented mode QR code reader and generator.
+type QR struct{}
+
+// NewQR returns a new QR instance.
+func NewQR() *QR {
+	return &QR{}
+}
+
+// GeneratePayload encodes a payload into its base64 form. It is mostly useful to build test fixtures.
+func (qr *QR) GeneratePayload(payload Payload) (string, error) {
+	if err := payload.Validate(); err != nil {
+		return "", err
+	}
+
+	tlvs := []TLV{{Tag: TagPayloadFormatIndicator, Value: []byte(payload.PayloadFormatIndicator)}}
+	for _, application := range payload.Applications {
+		children, err := encodeApplication(application)
+		if err != nil {
+			return "", err
+		}
+		tlvs = append(tlvs, TLV{Tag: TagApplicationTemplate, Children: children})
+	}
+	if payload.CommonData != nil {
+		children, err := encodeApplication(*payload.CommonData)
+		if err != nil {
+			return "", err
+		}
+		tlvs = append(tlvs, TLV{Tag: TagCommonDataTemplate, Children: children})
+	}
+
+	data, err := EncodeTLV(tlvs)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// Reader decodes and validates a base64 consumer presented mode payload.
+func (qr *QR) Reader(data string) (*Payload, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(data))
+	if err != nil {
+		return nil, errors.New("invalid base64")
+	}
+	tlvs, err := DecodeTLV(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &Payload{Applications: make([]Application, 0)}
+	for _, tlv := range tlvs {
+		switch tlv.Tag {
+		case TagPayloadFormatIndicator:
+			payload.PayloadFormatIndicator = string(tlv.Value)
+		case TagApplicationTemplate:
+			payload.Applications = append(payload.Applications, decodeApplication(tlv.Children))
+		case TagCommonDataTemplate:
+			commonData := decodeApplication(tlv.Children)
+			payload.CommonData = &commonData
+		}
+	}
+
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// Validate checks the mandatory data objects: the payload format indicator, at least one
+// application template with an AID, and track 2 equivalent data or a PAN for every
+// application, either in its own template or in the common data template.
+func (p *Payload) Validate() error {
+	if p.PayloadFormatIndicator != PayloadFormatIndicatorCPV01 {
+		return errors.New("invalid payload format indicator")
+	}
+	if len(p.Applications) == 0 {
+		return errors.New("application template is required")
+	}
+
+	commonAccount := p.CommonData != nil &&
+		(p.CommonData.Track2EquivalentData != "" || p.CommonData.ApplicationPAN != "")
+	for _, application := range p.Applications {
+		if application.AID == "" {
+			return errors.New("aid is required")
+		}
+		if application.Track2EquivalentData == "" && application.ApplicationPAN == "" && !commonAccount {
+			return errors.New("track 2 equivalent data or pan is required")
+		}
+	}
+	return nil
+}
+
+func decodeApplication(tlvs []TLV) Application {
+	var application Application
+	for _, tlv := range tlvs {
+		if tlv.Tag == TagApplicationSpecificTemplate {
+			application.ApplicationSpecific = tlv.Children
+			continue
+		}
+		mapped := false
+		for _, f := range applicationFields {
+			if f.tag == tlv.Tag {
+				*f.field(&application) = decodeValue(tlv.Value, f.format)
+				mapped = true
+				break
+			}
+		}
+		if !mapped {
+			application.Extra = append(application.Extra, tlv)
+		}
+	}
+	return application
+}
+
+func encodeApplication(application Application) ([]TLV, error) {
+	tlvs := make([]TLV, 0)
+	for _, f := range applicationFields {
+		value := *f.field(&application)
+		if value == "" {
+			continue
+		}
+		encoded, err := encodeValue(value, f.format)
+		if err != nil {
+			return nil, err
+		}
+		tlvs = append(tlvs, TLV{Tag: f.tag, Value: encoded})
+	}
+	if len(application.ApplicationSpecific) > 0 {
+		tlvs = append(tlvs, TLV{Tag: TagApplicationSpecificTemplate, Children: application.ApplicationSpecific})
+	}
+	return append(tlvs, application.Extra...), nil
+}
+
+func decodeValue(value []byte, format valueFormat) string {
+	switch format {
+	case formatBinary:
+		return strings.ToUpper(hex.EncodeToString(value))
+	case formatBCD:
+		return strings.TrimRight(strings.ToUpper(hex.EncodeToString(value)), bcdPadding)
+	default:
+		return string(value)
+	}
+}
+
+func encodeValue(value string, format valueFormat) ([]byte, error) {
+	switch format {
+	case formatBinary:
+		encoded, err := hex.DecodeString(value)
+		if err != nil {
+			return nil, errors.New("invalid hex value")
+		}
+		return encoded, nil
+	case formatBCD:
+		if len(value)%2 != 0 {
+			value += bcdPadding
+		}
+		encoded, err := hex.DecodeString(value)
+		if err != nil {
+			return nil, errors.New("invalid bcd value")
+		}
+		return encoded, nil
+	default:
+		return []byte(value), nil
+	}
+}
+
+// PAN returns the account number of the application, taken from its PAN or, failing
+// that, from the track 2 equivalent data.
+func (a Application) PAN() string {
+	if a.ApplicationPAN != "" {
+		return a.ApplicationPAN
+	}
+	pan, _, _ := strings.Cut(a.Track2EquivalentData, track2Separator)
+	return pan
+}
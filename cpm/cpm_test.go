@@ -0,0 +1,90 @@
+package cpm_test
+
+import (
+	"encoding/base64"
+	"github.com/Jdemon/thaiqr/cpm"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGenerateCPMPayloadMustValid(t *testing.T) {
+	qr := cpm.NewQR()
+	payload := cpm.Payload{
+		PayloadFormatIndicator: cpm.PayloadFormatIndicatorCPV01,
+		Applications: []cpm.Application{{
+			AID:              "A0000000031010",
+			ApplicationLabel: "VISA",
+			ApplicationPAN:   "4761739001010010",
+		}},
+	}
+	actualPayload, err := qr.GeneratePayload(payload)
+	assert.Nil(t, err)
+	assert.Equal(t, "hQVDUFYwMWEZTwegAAAAAxAQUARWSVNBWghHYXOQAQEAEA==", actualPayload)
+
+	result, err := qr.Reader(actualPayload)
+	assert.Nil(t, err)
+	assert.Equal(t, payload.PayloadFormatIndicator, result.PayloadFormatIndicator)
+	assert.Equal(t, payload.Applications, result.Applications)
+	assert.Nil(t, result.CommonData)
+}
+
+func TestReadCPMPayloadWithTrack2AndCommonData(t *testing.T) {
+	qr := cpm.NewQR()
+	data := "hQVDUFYwMWEeTwegAAAABBAQVwxUEzOQAAAVE9JRIgFjBZ82AgABYgtfIAhET0UvSk9ITg=="
+
+	result, err := qr.Reader(data)
+	assert.Nil(t, err)
+	assert.Len(t, result.Applications, 1)
+	application := result.Applications[0]
+	assert.Equal(t, "A0000000041010", application.AID)
+	assert.Equal(t, "5413339000001513D2512201", application.Track2EquivalentData)
+	assert.Equal(t, "5413339000001513", application.PAN())
+	assert.Equal(t, []cpm.TLV{{Tag: "9F36", Value: []byte{0x00, 0x01}}}, application.ApplicationSpecific)
+	assert.Equal(t, "DOE/JOHN", result.CommonData.CardholderName)
+
+	regenerated, err := qr.GeneratePayload(*result)
+	assert.Nil(t, err)
+	assert.Equal(t, data, regenerated)
+}
+
+func TestReadCPMPayloadInvalid(t *testing.T) {
+	qr := cpm.NewQR()
+
+	_, err := qr.Reader("not base64!")
+	assert.Error(t, err)
+
+	// 85 05 CPV01 only: no application template
+	_, err = qr.Reader(base64.StdEncoding.EncodeToString(append([]byte{0x85, 0x05}, "CPV01"...)))
+	assert.Error(t, err)
+
+	// application template without PAN or track 2 equivalent data
+	_, err = qr.GeneratePayload(cpm.Payload{
+		PayloadFormatIndicator: cpm.PayloadFormatIndicatorCPV01,
+		Applications:           []cpm.Application{{AID: "A0000000031010"}},
+	})
+	assert.Error(t, err)
+
+	_, err = qr.GeneratePayload(cpm.Payload{
+		PayloadFormatIndicator: "CPV02",
+		Applications:           []cpm.Application{{AID: "A0000000031010", ApplicationPAN: "4761739001010010"}},
+	})
+	assert.Error(t, err)
+}
+
+func TestBERTLV(t *testing.T) {
+	tlvs, err := cpm.DecodeTLV([]byte{0x9F, 0x26, 0x02, 0xAB, 0xCD, 0x00, 0x5A, 0x81, 0x01, 0x12})
+	assert.Nil(t, err)
+	assert.Equal(t, []cpm.TLV{
+		{Tag: "9F26", Value: []byte{0xAB, 0xCD}},
+		{Tag: "5A", Value: []byte{0x12}},
+	}, tlvs)
+
+	encoded, err := cpm.EncodeTLV([]cpm.TLV{{Tag: "50", Value: make([]byte, 200)}})
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x50, 0x81, 0xC8}, encoded[:3])
+
+	_, err = cpm.DecodeTLV([]byte{0x5A, 0x05, 0x01})
+	assert.Error(t, err)
+	_, err = cpm.DecodeTLV([]byte{0x9F})
+	assert.Error(t, err)
+}
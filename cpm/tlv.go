@@ -0,0 +1,140 @@
+package cpm
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// TLV is a BER-TLV data object. Tag is the upper case hex encoding of the tag bytes
+// (e.g. "5F20"); constructed objects carry their decoded content in Children.
+type TLV struct {
+	Tag      string `json:"tag"`
+	Value    []byte `json:"value,omitempty"`
+	Children []TLV  `json:"children,omitempty"`
+}
+
+// Constructed reports whether the tag denotes a constructed data object (bit 6 of the first byte).
+func (t TLV) Constructed() bool {
+	first, err := hex.DecodeString(t.Tag[:min(2, len(t.Tag))])
+	return err == nil && len(first) == 1 && first[0]&0x20 != 0
+}
+
+// Find returns the first child with the given tag.
+func (t TLV) Find(tag string) (TLV, bool) {
+	return find(t.Children, tag)
+}
+
+// DecodeTLV decodes a sequence of BER-TLV data objects. Constructed objects are decoded recursively.
+func DecodeTLV(data []byte) ([]TLV, error) {
+	tlvs := make([]TLV, 0)
+	for len(data) > 0 {
+		// 0x00 and 0xFF are padding between data objects.
+		if data[0] == 0x00 || data[0] == 0xFF {
+			data = data[1:]
+			continue
+		}
+
+		tagLen := 1
+		if data[0]&0x1F == 0x1F {
+			for {
+				if tagLen >= len(data) {
+					return nil, errors.New("truncated tag")
+				}
+				tagLen++
+				if data[tagLen-1]&0x80 == 0 {
+					break
+				}
+			}
+		}
+		tag := strings.ToUpper(hex.EncodeToString(data[:tagLen]))
+		data = data[tagLen:]
+
+		length, lengthLen, err := decodeLength(data)
+		if err != nil {
+			return nil, err
+		}
+		data = data[lengthLen:]
+		if length > len(data) {
+			return nil, errors.New("value exceeds remaining data")
+		}
+
+		tlv := TLV{Tag: tag, Value: data[:length]}
+		if tlv.Constructed() {
+			children, err := DecodeTLV(tlv.Value)
+			if err != nil {
+				return nil, err
+			}
+			tlv.Children = children
+		}
+		tlvs = append(tlvs, tlv)
+		data = data[length:]
+	}
+	return tlvs, nil
+}
+
+// EncodeTLV encodes a sequence of BER-TLV data objects. Constructed objects with Children
+// are encoded from their children, ignoring Value.
+func EncodeTLV(tlvs []TLV) ([]byte, error) {
+	out := make([]byte, 0)
+	for _, tlv := range tlvs {
+		tag, err := hex.DecodeString(tlv.Tag)
+		if err != nil || len(tag) == 0 {
+			return nil, errors.New("invalid tag")
+		}
+
+		value := tlv.Value
+		if tlv.Constructed() && len(tlv.Children) > 0 {
+			value, err = EncodeTLV(tlv.Children)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		out = append(out, tag...)
+		out = append(out, encodeLength(len(value))...)
+		out = append(out, value...)
+	}
+	return out, nil
+}
+
+// decodeLength decodes a BER length and returns it with the number of bytes it occupied.
+func decodeLength(data []byte) (int, int, error) {
+	if len(data) == 0 {
+		return 0, 0, errors.New("truncated length")
+	}
+	if data[0]&0x80 == 0 {
+		return int(data[0]), 1, nil
+	}
+
+	n := int(data[0] & 0x7F)
+	if n == 0 || n > 3 || n >= len(data) {
+		return 0, 0, errors.New("invalid length")
+	}
+	length := 0
+	for _, b := range data[1 : n+1] {
+		length = length<<8 | int(b)
+	}
+	return length, n + 1, nil
+}
+
+// encodeLength encodes a BER length in its shortest form.
+func encodeLength(length int) []byte {
+	switch {
+	case length < 0x80:
+		return []byte{byte(length)}
+	case length <= 0xFF:
+		return []byte{0x81, byte(length)}
+	default:
+		return []byte{0x82, byte(length >> 8), byte(length)}
+	}
+}
+
+func find(tlvs []TLV, tag string) (TLV, bool) {
+	for _, tlv := range tlvs {
+		if strings.EqualFold(tlv.Tag, tag) {
+			return tlv, true
+		}
+	}
+	return TLV{}, false
+}
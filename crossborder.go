@@ -0,0 +1,294 @@
+package thaiqr
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PaymentNetwork identifies the national payment network behind a QR.
+type PaymentNetwork string
+
+const (
+	PaymentNetworkPromptPay PaymentNetwork = "PROMPTPAY"
+	PaymentNetworkPayNow    PaymentNetwork = "PAYNOW"
+	PaymentNetworkDuitNow   PaymentNetwork = "DUITNOW"
+	PaymentNetworkBakong    PaymentNetwork = "BAKONG"
+	PaymentNetworkNAPAS     PaymentNetwork = "NAPAS"
+	PaymentNetworkQRIS      PaymentNetwork = "QRIS"
+	PaymentNetworkLAPNet    PaymentNetwork = "LAPNET"
+)
+
+const (
+	GUIDPayNow  = "SG.PAYNOW"
+	GUIDDuitNow = "A0000006150001"
+	GUIDNAPAS   = "A000000727"
+	GUIDQRIS    = "ID.CO.QRIS.WWW"
+	// GUIDLAPNet identifies the LAPNet merchant account template (tag 38). It is not backed by a
+	// published LAPNet specification and does not start with an ISO 7816-5 registered application
+	// provider ID, so check it against the LAPNet QR specification of the acquiring bank.
+	GUIDLAPNet = "A005266284662577"
+)
+
+// Linkage describes a bilateral QR payment linkage between PromptPay and a foreign network.
+type Linkage struct {
+	CountryCode string         `json:"countryCode"`
+	Currencies  []string       `json:"currencies"`
+	Network     PaymentNetwork `json:"network"`
+	GUID        string         `json:"guid,omitempty"`
+}
+
+var linkages = []Linkage{
+	{CountryCode: "SG", Currencies: []string{"SGD"}, Network: PaymentNetworkPayNow, GUID: GUIDPayNow},
+	{CountryCode: "MY", Currencies: []string{"MYR"}, Network: PaymentNetworkDuitNow, GUID: GUIDDuitNow},
+	{CountryCode: "KH", Currencies: []string{"KHR"}, Network: PaymentNetworkBakong},
+	{CountryCode: "VN", Currencies: []string{"VND"}, Network: PaymentNetworkNAPAS, GUID: GUIDNAPAS},
+	{CountryCode: "ID", Currencies: []string{"IDR"}, Network: PaymentNetworkQRIS, GUID: GUIDQRIS},
+	{CountryCode: CountryCodeLA, Currencies: []string{"LAK"}, Network: PaymentNetworkLAPNet, GUID: GUIDLAPNet},
+}
+
+// Linkages returns the bilateral QR payment linkages supported by the cross-border mode.
+func Linkages() []Linkage {
+	return slices.Clone(linkages)
+}
+
+// ValidateCrossBorder checks a country and currency combination against the bilateral linkages.
+//
+// Thai merchants may denominate a QR in THB or in the currency of any linked country, while
+// foreign merchants may only use their own currency. It returns the linkage of the foreign side,
+// or nil for a domestic TH/THB combination.
+func ValidateCrossBorder(countryCode, currency string) (*Linkage, error) {
+	countryCode = strings.ToUpper(countryCode)
+	currency = strings.ToUpper(currency)
+
+	if countryCode == CountryCodeTH && currency == "THB" {
+		return nil, nil
+	}
+
+	for _, linkage := range linkages {
+		if !slices.Contains(linkage.Currencies, currency) {
+			continue
+		}
+		if countryCode == CountryCodeTH || countryCode == linkage.CountryCode {
+			l := linkage
+			return &l, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no cross-border linkage for country %s and currency %s", countryCode, currency)
+}
+
+// linkageByGUID returns the linkage whose merchant account template carries guid.
+func linkageByGUID(guid string) *Linkage {
+	for _, linkage := range linkages {
+		if linkage.GUID != "" && strings.EqualFold(linkage.GUID, guid) {
+			l := linkage
+			return &l
+		}
+	}
+	return nil
+}
+
+// linkageByCountry returns the linkage of a foreign country.
+func linkageByCountry(countryCode string) *Linkage {
+	for _, linkage := range linkages {
+		if linkage.CountryCode == countryCode {
+			l := linkage
+			return &l
+		}
+	}
+	return nil
+}
+
+// MerchantAccount is a merchant account information template (tags 26-51) of a payment network.
+type MerchantAccount struct {
+	ID       string            `json:"id"`
+	GUID     string            `json:"guid"`
+	Network  PaymentNetwork    `json:"network,omitempty"`
+	Fields   map[string]string `json:"fields,omitempty"`
+	Segments *[]Segment        `json:"segments,omitempty"`
+}
+
+type PromptPayCrossBorderQRCmd struct {
+	ProxyID              string            `json:"proxyId"`
+	ProxyType            string            `json:"proxyType"`
+	Amount               string            `json:"Amount"`
+	OTA                  string            `json:"ota"`
+	CountryCode          string            `json:"countryCode"`
+	CurrencyCode         string            `json:"currencyCode"`
+	MerchantCategoryCode string            `json:"merchantCategoryCode"`
+	MerchantName         string            `json:"merchantName"`
+	MerchantCity         string            `json:"merchantCity"`
+	MerchantAccounts     []MerchantAccount `json:"merchantAccounts"`
+}
+
+// GenerateCrossBorderPayload generates a PromptPay QR code payload that can be paid from a linked foreign network.
+//
+// The country and currency must match a bilateral linkage whose network has a merchant account
+// GUID, which rules out Bakong. A foreign payload must carry at least one merchant account template,
+// and every template must belong to the network of that linkage; a domestic TH/THB payload carries none.
+func (qr *PromptPayQR) GenerateCrossBorderPayload(cmd PromptPayCrossBorderQRCmd) (string, error) {
+	countryCode := ifThenElse(cmd.CountryCode != "", cmd.CountryCode, CountryCodeTH).(string)
+	currency := ifThenElse(cmd.CurrencyCode != "", cmd.CurrencyCode, "THB").(string)
+	linkage, err := ValidateCrossBorder(countryCode, currency)
+	if err != nil {
+		return "", err
+	}
+	// Bakong templates carry the account ID in place of a GUID, so they cannot be told apart
+	// from other networks' templates in a cross-border payload.
+	if linkage != nil && linkage.GUID == "" {
+		return "", fmt.Errorf("cross-border payloads are not supported for the %s network", linkage.Network)
+	}
+
+	if strings.TrimSpace(cmd.MerchantName) == "" {
+		return "", errors.New("merchant name is required")
+	}
+	if strings.TrimSpace(cmd.MerchantCity) == "" {
+		return "", errors.New("merchant city is required")
+	}
+
+	proxyID := sanitizeTarget(cmd.ProxyID)
+	merchantInfoData := &payloadBuilder{}
+	merchantInfoData.field(BOTIDCreditTransferAID, GUIDPromptPay)
+	merchantInfoData.field(determineTargetType(cmd.ProxyType), formatTarget(proxyID))
+	if strings.TrimSpace(cmd.OTA) != "" {
+		merchantInfoData.field(BOTIDMerchantOTA, cmd.OTA)
+	}
+
+	if linkage == nil && len(cmd.MerchantAccounts) > 0 {
+		return "", errors.New("domestic payloads cannot carry foreign merchant accounts")
+	}
+	if linkage != nil && len(cmd.MerchantAccounts) == 0 {
+		return "", fmt.Errorf("a %s merchant account is required", linkage.Network)
+	}
+	accounts := map[string]*payloadBuilder{
+		IDMerchantInformationBOT: merchantInfoData,
+	}
+	for _, account := range cmd.MerchantAccounts {
+		id, value, err := encodeMerchantAccount(account, linkage)
+		if err != nil {
+			return "", err
+		}
+		if _, ok := accounts[id]; ok {
+			return "", fmt.Errorf("duplicate merchant account template %s", id)
+		}
+		accounts[id] = value
+	}
+
+	amount := strings.TrimSpace(cmd.Amount)
+	data := &payloadBuilder{}
+	data.field(IDPayloadFormat, PayloadFormatEMVQRCPSMerchantPresentedMode)
+	data.field(IDPOIMethod, ifThenElse(amount != "", POIMethodDynamic, POIMethodStatic).(string))
+	for _, id := range sortedKeys(accounts) {
+		data.template(id, accounts[id])
+	}
+
+	data.field(IDMerchantCategoryCode, ifThenElse(cmd.MerchantCategoryCode != "", cmd.MerchantCategoryCode, "0000").(string))
+	data.field(IDTransactionCurrency, currencyCode[strings.ToUpper(currency)])
+	if amount != "" {
+		amountFormat, err := formatAmountWithExponent(amount, crossBorderCurrencyExponent(currency))
+		if err != nil {
+			return "", err
+		}
+		data.field(IDTransactionAmount, amountFormat)
+	}
+	data.field(IDCountryCode, strings.ToUpper(countryCode))
+	data.field(IDMerchantName, cmd.MerchantName)
+	data.field(IDMerchantCity, cmd.MerchantCity)
+
+	return data.withChecksum(IDCRC)
+}
+
+// encodeMerchantAccount validates a foreign merchant account template against the linkage of
+// the payload and serializes its value.
+func encodeMerchantAccount(account MerchantAccount, linkage *Linkage) (string, *payloadBuilder, error) {
+	if !isMerchantAccountID(account.ID) || isPromptPayTemplateID(account.ID) {
+		return "", nil, fmt.Errorf("invalid merchant account template %s", account.ID)
+	}
+	accountLinkage := linkageByGUID(account.GUID)
+	if accountLinkage == nil {
+		return "", nil, fmt.Errorf("merchant account %s does not belong to a linked network", account.GUID)
+	}
+	if accountLinkage.Network != linkage.Network {
+		return "", nil, fmt.Errorf("merchant account %s does not belong to the %s network", account.GUID, linkage.Network)
+	}
+
+	fields := &payloadBuilder{}
+	fields.field("00", account.GUID)
+	for _, id := range sortedKeys(account.Fields) {
+		if id == "00" {
+			continue
+		}
+		fields.field(id, account.Fields[id])
+	}
+	return account.ID, fields, nil
+}
+
+// decodeMerchantAccounts returns the merchant account templates of a payload that do not carry a PromptPay AID.
+func decodeMerchantAccounts(segments []Segment) []MerchantAccount {
+	accounts := make([]MerchantAccount, 0)
+	for _, segment := range segments {
+		if !isMerchantAccountID(segment.ID) {
+			continue
+		}
+		fields, subSegments, err := deserialize(segment.Value)
+		if err != nil || strings.HasPrefix(fields["00"], GUIDPromptPayPrefix) {
+			continue
+		}
+		account := MerchantAccount{
+			ID:       segment.ID,
+			GUID:     fields["00"],
+			Fields:   fields,
+			Segments: &subSegments,
+		}
+		if linkage := linkageByGUID(account.GUID); linkage != nil {
+			account.Network = linkage.Network
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts
+}
+
+// detectPaymentNetwork determines the network of a merchant presented payload from its
+// templates, falling back to the linkage of its country code.
+func detectPaymentNetwork(results *PromptPayQRResults) PaymentNetwork {
+	if results.CreditTransfer != nil || results.BillPayment != nil {
+		return PaymentNetworkPromptPay
+	}
+	for _, account := range results.MerchantAccounts {
+		if account.Network != "" {
+			return account.Network
+		}
+	}
+	if linkage := linkageByCountry(results.CountryCode); linkage != nil {
+		return linkage.Network
+	}
+	return ""
+}
+
+// crossBorderCurrencyExponent returns the number of minor unit digits the linked networks use for a
+// currency: none for VND, IDR and LAK, as in their domestic QRs, and two otherwise.
+func crossBorderCurrencyExponent(currency string) int {
+	if slices.Contains([]string{"VND", "IDR", "LAK"}, strings.ToUpper(currency)) {
+		return 0
+	}
+	return 2
+}
+
+// isMerchantAccountID reports whether id is a merchant account information template (26-51).
+func isMerchantAccountID(id string) bool {
+	n, err := strconv.Atoi(id)
+	return err == nil && len(id) == 2 && n >= 26 && n <= 51
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
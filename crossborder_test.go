@@ -0,0 +1,163 @@
+package thaiqr_test
+
+import (
+	"github.com/Jdemon/thaiqr"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValidateCrossBorder(t *testing.T) {
+	linkage, err := thaiqr.ValidateCrossBorder(thaiqr.CountryCodeTH, "THB")
+	assert.Nil(t, err)
+	assert.Nil(t, linkage)
+
+	linkage, err = thaiqr.ValidateCrossBorder(thaiqr.CountryCodeTH, "SGD")
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.PaymentNetworkPayNow, linkage.Network)
+
+	linkage, err = thaiqr.ValidateCrossBorder("MY", "MYR")
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.PaymentNetworkDuitNow, linkage.Network)
+
+	_, err = thaiqr.ValidateCrossBorder("SG", "MYR")
+	assert.Error(t, err)
+
+	_, err = thaiqr.ValidateCrossBorder("BN", "BND")
+	assert.Error(t, err)
+}
+
+func TestGenerateCrossBorderPayload(t *testing.T) {
+	qr := thaiqr.NewPromptPayQR()
+	payload, err := qr.GenerateCrossBorderPayload(thaiqr.PromptPayCrossBorderQRCmd{
+		ProxyID:      "0909764856",
+		ProxyType:    thaiqr.ProxyTypeMsisdn,
+		Amount:       "25.50",
+		CurrencyCode: "SGD",
+		MerchantName: "THAI SHOP",
+		MerchantCity: "BANGKOK",
+		MerchantAccounts: []thaiqr.MerchantAccount{
+			{ID: "26", GUID: thaiqr.GUIDPayNow, Fields: map[string]string{"01": "2", "02": "T08GB0001A"}},
+		},
+	})
+	assert.Nil(t, err)
+	assert.True(t, thaiqr.VerifyPayloadChecksum(payload))
+
+	result, err := qr.Reader(payload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.PaymentNetworkPromptPay, result.PaymentNetwork)
+	assert.Equal(t, thaiqr.QRKindCrossBorder, result.Kind())
+	assert.Equal(t, "SGD", result.TransactionCurrencyCode)
+	assert.Equal(t, "25.50", result.TransactionAmount)
+	assert.Equal(t, "THAI SHOP", result.MerchantName)
+	assert.Equal(t, "0066909764856", result.CreditTransfer.MSISDN)
+	assert.Len(t, result.MerchantAccounts, 1)
+	assert.Equal(t, thaiqr.PaymentNetworkPayNow, result.MerchantAccounts[0].Network)
+	assert.Equal(t, "T08GB0001A", result.MerchantAccounts[0].Fields["02"])
+}
+
+func TestGenerateCrossBorderPayloadInvalid(t *testing.T) {
+	qr := thaiqr.NewPromptPayQR()
+	cmd := thaiqr.PromptPayCrossBorderQRCmd{
+		ProxyID:      "0909764856",
+		ProxyType:    thaiqr.ProxyTypeMsisdn,
+		CountryCode:  "SG",
+		CurrencyCode: "MYR",
+		MerchantName: "THAI SHOP",
+		MerchantCity: "BANGKOK",
+	}
+	_, err := qr.GenerateCrossBorderPayload(cmd)
+	assert.Error(t, err)
+
+	cmd.CountryCode = thaiqr.CountryCodeTH
+	cmd.MerchantAccounts = []thaiqr.MerchantAccount{{ID: "26", GUID: "XX.UNKNOWN"}}
+	_, err = qr.GenerateCrossBorderPayload(cmd)
+	assert.Error(t, err)
+
+	cmd.MerchantAccounts = []thaiqr.MerchantAccount{{ID: thaiqr.IDMerchantInformationBOT, GUID: thaiqr.GUIDPayNow}}
+	_, err = qr.GenerateCrossBorderPayload(cmd)
+	assert.Error(t, err)
+
+	cmd.MerchantAccounts = nil
+	cmd.CurrencyCode = "KHR"
+	_, err = qr.GenerateCrossBorderPayload(cmd)
+	assert.EqualError(t, err, "cross-border payloads are not supported for the BAKONG network")
+
+	cmd.CountryCode = "KH"
+	_, err = qr.GenerateCrossBorderPayload(cmd)
+	assert.EqualError(t, err, "cross-border payloads are not supported for the BAKONG network")
+
+	cmd.CountryCode = thaiqr.CountryCodeTH
+	cmd.CurrencyCode = "MYR"
+	cmd.MerchantName = ""
+	_, err = qr.GenerateCrossBorderPayload(cmd)
+	assert.Error(t, err)
+}
+
+func TestGenerateCrossBorderPayloadRequiresLinkedAccount(t *testing.T) {
+	qr := thaiqr.NewPromptPayQR()
+	payNow := thaiqr.MerchantAccount{ID: "26", GUID: thaiqr.GUIDPayNow, Fields: map[string]string{"01": "2", "02": "T08GB0001A"}}
+	duitNow := thaiqr.MerchantAccount{ID: "27", GUID: thaiqr.GUIDDuitNow, Fields: map[string]string{"01": "890053"}}
+	cmd := thaiqr.PromptPayCrossBorderQRCmd{
+		ProxyID:      "0909764856",
+		ProxyType:    thaiqr.ProxyTypeMsisdn,
+		CurrencyCode: "SGD",
+		MerchantName: "THAI SHOP",
+		MerchantCity: "BANGKOK",
+	}
+	_, err := qr.GenerateCrossBorderPayload(cmd)
+	assert.EqualError(t, err, "a PAYNOW merchant account is required")
+
+	cmd.MerchantAccounts = []thaiqr.MerchantAccount{duitNow}
+	_, err = qr.GenerateCrossBorderPayload(cmd)
+	assert.EqualError(t, err, "merchant account A0000006150001 does not belong to the PAYNOW network")
+
+	cmd.MerchantAccounts = []thaiqr.MerchantAccount{payNow, duitNow}
+	_, err = qr.GenerateCrossBorderPayload(cmd)
+	assert.Error(t, err)
+
+	cmd.CurrencyCode = "THB"
+	cmd.MerchantAccounts = []thaiqr.MerchantAccount{payNow}
+	_, err = qr.GenerateCrossBorderPayload(cmd)
+	assert.EqualError(t, err, "domestic payloads cannot carry foreign merchant accounts")
+}
+
+func TestGenerateCrossBorderPayloadAmount(t *testing.T) {
+	qr := thaiqr.NewPromptPayQR()
+	cmd := thaiqr.PromptPayCrossBorderQRCmd{
+		ProxyID:      "0909764856",
+		ProxyType:    thaiqr.ProxyTypeMsisdn,
+		Amount:       "1234567.89",
+		CurrencyCode: "SGD",
+		MerchantName: "THAI SHOP",
+		MerchantCity: "BANGKOK",
+		MerchantAccounts: []thaiqr.MerchantAccount{
+			{ID: "26", GUID: thaiqr.GUIDPayNow, Fields: map[string]string{"01": "2", "02": "T08GB0001A"}},
+		},
+	}
+	payload, err := qr.GenerateCrossBorderPayload(cmd)
+	assert.Nil(t, err)
+	assert.Contains(t, payload, "54101234567.89")
+
+	cmd.Amount = "-5"
+	_, err = qr.GenerateCrossBorderPayload(cmd)
+	assert.EqualError(t, err, "invalid amount")
+
+	// VND has no minor units
+	cmd.Amount = "50000.5"
+	cmd.CurrencyCode = "VND"
+	cmd.MerchantAccounts = []thaiqr.MerchantAccount{{ID: "38", GUID: thaiqr.GUIDNAPAS, Fields: map[string]string{"01": "970436"}}}
+	_, err = qr.GenerateCrossBorderPayload(cmd)
+	assert.EqualError(t, err, "invalid amount")
+
+	cmd.Amount = "50000"
+	payload, err = qr.GenerateCrossBorderPayload(cmd)
+	assert.Nil(t, err)
+	assert.Contains(t, payload, "540550000")
+}
+
+func TestReaderForeignPaymentNetwork(t *testing.T) {
+	result, err := thaiqr.NewPromptPayQR().Reader("00020101021126330009SG.PAYNOW010100211+658123456753037025802SG5902AB6009Singapore630495CE")
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.PaymentNetworkPayNow, result.PaymentNetwork)
+	assert.Equal(t, thaiqr.QRKindOther, result.Kind())
+}
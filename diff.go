@@ -0,0 +1,98 @@
+package thaiqr
+
+import (
+	"fmt"
+)
+
+// ChangeType describes how a field differs between two payloads.
+type ChangeType string
+
+const (
+	ChangeAdded   ChangeType = "ADDED"
+	ChangeRemoved ChangeType = "REMOVED"
+	ChangeChanged ChangeType = "CHANGED"
+)
+
+// Change is a single field difference reported by Diff.
+type Change struct {
+	Path string     `json:"path"`
+	Name string     `json:"name"`
+	Type ChangeType `json:"type"`
+	Old  string     `json:"old,omitempty"`
+	New  string     `json:"new,omitempty"`
+}
+
+// String formats the change as "54: 100.00 → 1000.00".
+func (c Change) String() string {
+	switch c.Type {
+	case ChangeAdded:
+		return fmt.Sprintf("%s added: %s", c.Path, c.New)
+	case ChangeRemoved:
+		return fmt.Sprintf("%s removed: %s", c.Path, c.Old)
+	default:
+		return fmt.Sprintf("%s: %s → %s", c.Path, c.Old, c.New)
+	}
+}
+
+// Diff compares two payloads field by field and reports added, removed and changed
+// fields by path. Nested templates are compared per sub-field, and the CRC field is
+// ignored since it changes with any other difference.
+func Diff(a, b string) ([]Change, error) {
+	explanationA, err := Explain(a)
+	if err != nil {
+		return nil, err
+	}
+	explanationB, err := Explain(b)
+	if err != nil {
+		return nil, err
+	}
+
+	leavesA := flattenExplainNodes(explanationA.Type, explanationA.Nodes, nil)
+	leavesB := flattenExplainNodes(explanationB.Type, explanationB.Nodes, nil)
+
+	indexB := make(map[string]ExplainNode, len(leavesB))
+	for _, node := range leavesB {
+		indexB[node.Path] = node
+	}
+
+	changes := make([]Change, 0)
+	seen := make(map[string]bool, len(leavesA))
+	for _, nodeA := range leavesA {
+		seen[nodeA.Path] = true
+		nodeB, ok := indexB[nodeA.Path]
+		if !ok {
+			changes = append(changes, Change{Path: nodeA.Path, Name: nodeA.Name, Type: ChangeRemoved, Old: nodeA.Value})
+			continue
+		}
+		if nodeA.Value != nodeB.Value {
+			changes = append(changes, Change{Path: nodeA.Path, Name: nodeA.Name, Type: ChangeChanged, Old: nodeA.Value, New: nodeB.Value})
+		}
+	}
+	for _, nodeB := range leavesB {
+		if !seen[nodeB.Path] {
+			changes = append(changes, Change{Path: nodeB.Path, Name: nodeB.Name, Type: ChangeAdded, New: nodeB.Value})
+		}
+	}
+
+	return changes, nil
+}
+
+// flattenExplainNodes returns the leaf fields of an explanation tree, skipping the CRC.
+func flattenExplainNodes(qrType QRType, nodes []ExplainNode, leaves []ExplainNode) []ExplainNode {
+	crcID := IDCRC
+	if qrType == QRTypeVerifyPaySlip {
+		crcID = IDQrVerifyCRC
+	}
+
+	for _, node := range nodes {
+		if node.Path == crcID {
+			continue
+		}
+		if len(node.Children) > 0 {
+			leaves = flattenExplainNodes(qrType, node.Children, leaves)
+			continue
+		}
+		leaves = append(leaves, node)
+	}
+	return leaves
+}
@@ -0,0 +1,68 @@
+package thaiqr_test
+
+import (
+	"github.com/Jdemon/thaiqr"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDiffChangedAmount(t *testing.T) {
+	qr := thaiqr.NewPromptPayQR()
+	cmd := thaiqr.PromptPayQRCmd{
+		ProxyID:   "0909764856",
+		ProxyType: thaiqr.ProxyTypeMsisdn,
+		Amount:    "100.00",
+	}
+	a, err := qr.GeneratePayload(cmd)
+	assert.Nil(t, err)
+	cmd.Amount = "1000.00"
+	b, err := qr.GeneratePayload(cmd)
+	assert.Nil(t, err)
+
+	changes, err := thaiqr.Diff(a, b)
+	assert.Nil(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, thaiqr.ChangeChanged, changes[0].Type)
+	assert.Equal(t, "54: 100.00 → 1000.00", changes[0].String())
+}
+
+func TestDiffNestedAddedRemoved(t *testing.T) {
+	changes, err := thaiqr.Diff(
+		"00020101021229370016A0000006770101110113006690976485653037645802TH540510.006304CF65",
+		"00020101021229370016A0000006770101110213110060146718253037645802TH540510.0063049A7C",
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, []thaiqr.Change{
+		{Path: "29.01", Name: "MSISDN", Type: thaiqr.ChangeRemoved, Old: "0066909764856"},
+		{Path: "29.02", Name: "National ID / Tax ID", Type: thaiqr.ChangeAdded, New: "1100601467182"},
+	}, changes)
+}
+
+func TestDiffIdentical(t *testing.T) {
+	payload := "003700060000010103006021620231130773524225102TH9104EC49"
+	changes, err := thaiqr.Diff(payload, payload)
+	assert.Nil(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestDiffIgnoresChecksum(t *testing.T) {
+	changes, err := thaiqr.Diff(
+		"00020101021229370016A0000006770101110113006690976485653037645802TH540510.006304CF65",
+		"00020101021229370016A0000006770101110113006690976485653037645802TH540510.0063040000",
+	)
+	assert.Nil(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestDiffMalformedPayload(t *testing.T) {
+	valid := "00020101021229370016A0000006770101110113006690976485653037645802TH540510.006304CF65"
+	for _, pair := range [][2]string{
+		{"abc", "000201"},
+		{"", valid},
+		{valid, "ab"},
+		{valid, "000201010212299"},
+	} {
+		_, err := thaiqr.Diff(pair[0], pair[1])
+		assert.Error(t, err, pair)
+	}
+}
@@ -0,0 +1,170 @@
+package thaiqr
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	// DuitNowIDAID DuitNow Merchant Account Information Tag 26
+	DuitNowIDAID        = "00"
+	DuitNowIDAcquirerID = "01"
+	DuitNowIDMerchantID = "02"
+
+	DuitNowIDTag62BillNumber           = "01"
+	DuitNowIDTag62ReferenceLabel       = "05"
+	DuitNowIDTag62TerminalID           = "07"
+	DuitNowIDTag62PurposeOfTransaction = "08"
+
+	IDMerchantInformationDuitNow = "26"
+	TransactionCurrencyMYR       = "458"
+	CountryCodeMY                = "MY"
+)
+
+type DuitNowQRCmd struct {
+	AcquirerID           string `json:"acquirerId"`
+	MerchantID           string `json:"merchantId"`
+	Amount               string `json:"amount"`
+	CurrencyCode         string `json:"currencyCode"`
+	MerchantCategoryCode string `json:"merchantCategoryCode"`
+	MerchantName         string `json:"merchantName"`
+	MerchantCity         string `json:"merchantCity"`
+	PostalCode           string `json:"postalCode"`
+	BillNumber           string `json:"billNumber"`
+	ReferenceLabel       string `json:"referenceLabel"`
+	TerminalID           string `json:"terminalId"`
+	PurposeOfTransaction string `json:"purposeOfTransaction"`
+}
+
+type DuitNowQRResults struct {
+	PayloadFormatIndicator  string     `json:"payloadFormatIndicator"`
+	PointOfInitiationMethod string     `json:"pointOfInitiationMethod"`
+	AID                     string     `json:"aid"`
+	AcquirerID              string     `json:"acquirerId"`
+	MerchantID              string     `json:"merchantId"`
+	MerchantCategoryCode    string     `json:"merchantCategoryCode,omitempty"`
+	TransactionCurrency     string     `json:"transactionCurrency"`
+	TransactionCurrencyCode string     `json:"transactionCurrencyCode"`
+	TransactionAmount       string     `json:"transactionAmount,omitempty"`
+	CountryCode             string     `json:"countryCode"`
+	MerchantName            string     `json:"merchantName,omitempty"`
+	MerchantCity            string     `json:"merchantCity,omitempty"`
+	PostalCode              string     `json:"postalCode,omitempty"`
+	BillNumber              string     `json:"billNumber,omitempty"`
+	ReferenceLabel          string     `json:"referenceLabel,omitempty"`
+	TerminalID              string     `json:"terminalId,omitempty"`
+	PurposeOfTransaction    string     `json:"purposeOfTransaction,omitempty"`
+	CRC                     string     `json:"crc"`
+	Segments                *[]Segment `json:"segments,omitempty"`
+}
+
+// DuitNowQR represents a Malaysia DuitNow QR code generator.
+type DuitNowQR struct{}
+
+// NewDuitNowQR returns a new DuitNowQR instance.
+func NewDuitNowQR() *DuitNowQR {
+	return &DuitNowQR{}
+}
+
+// GeneratePayload generates a DuitNow QR code payload.
+func (qr *DuitNowQR) GeneratePayload(cmd DuitNowQRCmd) (string, error) {
+	if cmd.CurrencyCode != "" && strings.ToUpper(cmd.CurrencyCode) != "MYR" {
+		return "", errors.New("invalid currency")
+	}
+	acquirerID := sanitizeTarget(cmd.AcquirerID)
+	if acquirerID == "" {
+		return "", errors.New("acquirer id is required")
+	}
+	if strings.TrimSpace(cmd.MerchantID) == "" {
+		return "", errors.New("merchant id is required")
+	}
+
+	amount := strings.TrimSpace(cmd.Amount)
+	merchantInfoData := &payloadBuilder{}
+	merchantInfoData.field(DuitNowIDAID, GUIDDuitNow)
+	merchantInfoData.field(DuitNowIDAcquirerID, acquirerID)
+	merchantInfoData.field(DuitNowIDMerchantID, cmd.MerchantID)
+
+	data := &payloadBuilder{}
+	data.field(IDPayloadFormat, PayloadFormatEMVQRCPSMerchantPresentedMode)
+	data.field(IDPOIMethod, ifThenElse(amount != "", POIMethodDynamic, POIMethodStatic).(string))
+	data.template(IDMerchantInformationDuitNow, merchantInfoData)
+	data.field(IDMerchantCategoryCode, ifThenElse(cmd.MerchantCategoryCode != "", cmd.MerchantCategoryCode, "0000").(string))
+	data.field(IDTransactionCurrency, TransactionCurrencyMYR)
+	if amount != "" {
+		amountFormat, err := formatAmountWithExponent(amount, 2)
+		if err != nil {
+			return "", err
+		}
+		data.field(IDTransactionAmount, amountFormat)
+	}
+	data.field(IDCountryCode, CountryCodeMY)
+	data.field(IDMerchantName, ifThenElse(cmd.MerchantName != "", cmd.MerchantName, "NA").(string))
+	data.field(IDMerchantCity, ifThenElse(cmd.MerchantCity != "", cmd.MerchantCity, "Kuala Lumpur").(string))
+	if strings.TrimSpace(cmd.PostalCode) != "" {
+		data.field(IDPostalCode, cmd.PostalCode)
+	}
+
+	additionalData := &payloadBuilder{}
+	for _, field := range [][2]string{
+		{DuitNowIDTag62BillNumber, cmd.BillNumber},
+		{DuitNowIDTag62ReferenceLabel, cmd.ReferenceLabel},
+		{DuitNowIDTag62TerminalID, cmd.TerminalID},
+		{DuitNowIDTag62PurposeOfTransaction, cmd.PurposeOfTransaction},
+	} {
+		if strings.TrimSpace(field[1]) != "" {
+			additionalData.field(field[0], field[1])
+		}
+	}
+	if !additionalData.empty() {
+		data.template(IDAdditionalFields, additionalData)
+	}
+
+	return data.withChecksum(IDCRC)
+}
+
+// Reader decodes a DuitNow QR code payload.
+func (qr *DuitNowQR) Reader(data string) (*DuitNowQRResults, error) {
+	qrFields, qrSegments, err := deserializeMerchantPresented(data)
+	if err != nil {
+		return nil, err
+	}
+
+	_, merchantFields, _, ok := findMerchantAccount(qrSegments, GUIDDuitNow)
+	if !ok {
+		return nil, errors.New("duitnow merchant account not found")
+	}
+
+	transactionCurrency := qrFields[IDTransactionCurrency]
+	if transactionCurrency != TransactionCurrencyMYR {
+		return nil, errors.New("invalid currency")
+	}
+	countryCode := qrFields[IDCountryCode]
+	if len(countryCode) != 2 {
+		return nil, invalidFormat()
+	}
+
+	additionalFields, _, _ := deserialize(qrFields[IDAdditionalFields])
+
+	return &DuitNowQRResults{
+		PayloadFormatIndicator:  qrFields[IDPayloadFormat],
+		PointOfInitiationMethod: qrFields[IDPOIMethod],
+		AID:                     merchantFields[DuitNowIDAID],
+		AcquirerID:              merchantFields[DuitNowIDAcquirerID],
+		MerchantID:              merchantFields[DuitNowIDMerchantID],
+		MerchantCategoryCode:    qrFields[IDMerchantCategoryCode],
+		TransactionCurrency:     transactionCurrency,
+		TransactionCurrencyCode: GetCurrencyCode(transactionCurrency),
+		TransactionAmount:       qrFields[IDTransactionAmount],
+		CountryCode:             countryCode,
+		MerchantName:            qrFields[IDMerchantName],
+		MerchantCity:            qrFields[IDMerchantCity],
+		PostalCode:              qrFields[IDPostalCode],
+		BillNumber:              additionalFields[DuitNowIDTag62BillNumber],
+		ReferenceLabel:          additionalFields[DuitNowIDTag62ReferenceLabel],
+		TerminalID:              additionalFields[DuitNowIDTag62TerminalID],
+		PurposeOfTransaction:    additionalFields[DuitNowIDTag62PurposeOfTransaction],
+		CRC:                     qrFields[IDCRC],
+		Segments:                &qrSegments,
+	}, nil
+}
@@ -0,0 +1,82 @@
+package thaiqr_test
+
+import (
+	"github.com/Jdemon/thaiqr"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGenerateDuitNowMustValid(t *testing.T) {
+	qr := thaiqr.NewDuitNowQR()
+	cmd := thaiqr.DuitNowQRCmd{
+		AcquirerID:           "890053",
+		MerchantID:           "MBBQR1234567",
+		Amount:               "15.90",
+		MerchantCategoryCode: "5812",
+		MerchantName:         "NASI LEMAK",
+		BillNumber:           "B001",
+		TerminalID:           "T01",
+	}
+	actualPayload, err := qr.GeneratePayload(cmd)
+	assert.Nil(t, err)
+	assert.Equal(t, "00020101021226440014A000000615000101068900530212MBBQR12345675204581253034585405"+
+		"15.905802MY5910NASI LEMAK6012Kuala Lumpur62150104B0010703T016304"+actualPayload[len(actualPayload)-4:], actualPayload)
+
+	result, err := qr.Reader(actualPayload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.GUIDDuitNow, result.AID)
+	assert.Equal(t, cmd.AcquirerID, result.AcquirerID)
+	assert.Equal(t, cmd.MerchantID, result.MerchantID)
+	assert.Equal(t, cmd.Amount, result.TransactionAmount)
+	assert.Equal(t, "MYR", result.TransactionCurrencyCode)
+	assert.Equal(t, thaiqr.CountryCodeMY, result.CountryCode)
+	assert.Equal(t, cmd.BillNumber, result.BillNumber)
+	assert.Equal(t, cmd.TerminalID, result.TerminalID)
+	assert.Equal(t, actualPayload[len(actualPayload)-4:], result.CRC)
+
+	parsed, err := thaiqr.Parse(actualPayload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.QRTypeDuitNow, parsed.Type)
+	assert.Equal(t, cmd.MerchantID, parsed.DuitNow.MerchantID)
+}
+
+func TestGenerateDuitNowInvalidCurrency(t *testing.T) {
+	_, err := thaiqr.NewDuitNowQR().GeneratePayload(thaiqr.DuitNowQRCmd{
+		AcquirerID:   "890053",
+		MerchantID:   "MBBQR1234567",
+		CurrencyCode: "THB",
+	})
+	assert.Error(t, err)
+}
+
+func TestGenerateDuitNowAmount(t *testing.T) {
+	qr := thaiqr.NewDuitNowQR()
+	cmd := thaiqr.DuitNowQRCmd{AcquirerID: "890053", MerchantID: "MBBQR1234567", Amount: "1234567.89"}
+	payload, err := qr.GeneratePayload(cmd)
+	assert.Nil(t, err)
+	result, err := qr.Reader(payload)
+	assert.Nil(t, err)
+	assert.Equal(t, "1234567.89", result.TransactionAmount)
+
+	cmd.Amount = "-5"
+	_, err = qr.GeneratePayload(cmd)
+	assert.EqualError(t, err, "invalid amount")
+}
+
+func TestGenerateDuitNowMissingMerchant(t *testing.T) {
+	_, err := thaiqr.NewDuitNowQR().GeneratePayload(thaiqr.DuitNowQRCmd{AcquirerID: "890053"})
+	assert.Error(t, err)
+}
+
+func TestDuitNowReaderRejectsNonMYR(t *testing.T) {
+	// DuitNow merchant account template with a THB currency.
+	_, err := thaiqr.NewDuitNowQR().Reader("00020101021126440014A000000615000101068900530212MBBQR123456753037645802MY5902AB6002KL6304CA74")
+	assert.EqualError(t, err, "invalid currency")
+}
+
+func TestReadDuitNowShortPayload(t *testing.T) {
+	for _, data := range []string{"", "ab", "abcd", "000201"} {
+		_, err := thaiqr.NewDuitNowQR().Reader(data)
+		assert.Error(t, err, data)
+	}
+}
@@ -0,0 +1,169 @@
+package thaiqr
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"io"
+)
+
+// PaperWidth is the printable width of a thermal receipt printer in dots, at the usual 203 DPI.
+type PaperWidth int
+
+const (
+	PaperWidth58mm PaperWidth = 384
+	PaperWidth80mm PaperWidth = 576
+)
+
+// ESCPOSQRMode selects how the QR code is sent to the printer.
+type ESCPOSQRMode string
+
+const (
+	// ESCPOSQRNative sends the payload with the printer's GS ( k QR code commands, the smallest and
+	// sharpest output on printers that support them.
+	ESCPOSQRNative ESCPOSQRMode = "native"
+	// ESCPOSQRRaster sends the QR code as a GS v 0 raster bitmap, for printers without QR commands.
+	ESCPOSQRRaster ESCPOSQRMode = "raster"
+)
+
+// escposMaxModuleSize is the largest module size the GS ( k command accepts.
+const escposMaxModuleSize = 16
+
+// ESCPOSReceipt is a QR code printed centred on a receipt with text lines under it.
+type ESCPOSReceipt struct {
+	Payload string
+	// Lines are printed centred under the QR code, e.g. the merchant name and amount.
+	Lines []string
+	// PaperWidth defaults to PaperWidth58mm.
+	PaperWidth PaperWidth
+	// Mode defaults to ESCPOSQRNative.
+	Mode ESCPOSQRMode
+	// ThaiCodePage is the printer's code page number for TIS-620 Thai text, selected with ESC t when set.
+	// Lines are always encoded as TIS-620, so ASCII text needs no code page.
+	ThaiCodePage int
+	// Cut feeds the paper and cuts it after the last line.
+	Cut bool
+}
+
+// PromptPayESCPOSReceipt builds a receipt for a PromptPay QR, with the merchant name and amount under it.
+func PromptPayESCPOSReceipt(merchantName string, cmd PromptPayQRCmd) (ESCPOSReceipt, error) {
+	item, err := PromptPayPDFItem(merchantName, cmd)
+	if err != nil {
+		return ESCPOSReceipt{}, err
+	}
+	return ESCPOSReceipt{Payload: item.Payload, Lines: item.Captions}, nil
+}
+
+// WriteESCPOS writes the ESC/POS commands printing receipt to w, e.g. a printer connection or device file.
+// The error correction level and quiet zone come from WithRenderOptions; the module size is the largest
+// that fits the paper width.
+func WriteESCPOS(w io.Writer, receipt ESCPOSReceipt, opts ...Option) error {
+	ro := newOptions(opts).render.normalize()
+	paperWidth := int(receipt.PaperWidth)
+	if paperWidth == 0 {
+		paperWidth = int(PaperWidth58mm)
+	}
+	if paperWidth < 0 {
+		return errors.New("invalid paper width")
+	}
+
+	bitmap, err := encodeBitmap(receipt.Payload, ro.ErrorCorrection)
+	if err != nil {
+		return err
+	}
+	moduleSize := paperWidth / (len(bitmap) + 2*ro.QuietZone)
+	if moduleSize < 1 {
+		return errors.New("qr code does not fit the paper width")
+	}
+
+	var buf bytes.Buffer
+	buf.Write([]byte{0x1B, 0x40})       // ESC @: initialise
+	buf.Write([]byte{0x1B, 0x61, 0x01}) // ESC a 1: centre
+	if receipt.ThaiCodePage > 0 {
+		buf.Write([]byte{0x1B, 0x74, byte(receipt.ThaiCodePage)}) // ESC t n: code page
+	}
+
+	switch receipt.Mode {
+	case "", ESCPOSQRNative:
+		writeESCPOSNativeQR(&buf, receipt.Payload, min(moduleSize, escposMaxModuleSize), ro.ErrorCorrection)
+	case ESCPOSQRRaster:
+		ro.ModuleSize = moduleSize
+		ro.Foreground, ro.Background, ro.Transparent = nil, nil, false
+		img, _, err := renderImage(receipt.Payload, ro.normalize())
+		if err != nil {
+			return err
+		}
+		writeESCPOSRaster(&buf, img)
+	default:
+		return errors.New("invalid escpos qr mode")
+	}
+	buf.WriteByte('\n')
+
+	for _, line := range receipt.Lines {
+		buf.Write(toTIS620(line))
+		buf.WriteByte('\n')
+	}
+	if receipt.Cut {
+		buf.Write([]byte{0x1D, 0x56, 0x42, 0x03}) // GS V 66 3: feed 3 lines and partial cut
+	}
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// writeESCPOSNativeQR writes the GS ( k commands selecting model 2, module size and error correction,
+// then storing and printing the payload.
+func writeESCPOSNativeQR(buf *bytes.Buffer, payload string, moduleSize int, level ErrorCorrectionLevel) {
+	levels := map[ErrorCorrectionLevel]byte{
+		ErrorCorrectionLow:      48,
+		ErrorCorrectionMedium:   49,
+		ErrorCorrectionQuartile: 50,
+		ErrorCorrectionHigh:     51,
+	}
+	qrFunction := func(fn byte, params ...byte) {
+		size := len(params) + 2
+		buf.Write([]byte{0x1D, 0x28, 0x6B, byte(size), byte(size >> 8), 0x31, fn})
+		buf.Write(params)
+	}
+
+	qrFunction(0x41, 0x32, 0x00) // function 165: model 2
+	qrFunction(0x43, byte(moduleSize))
+	qrFunction(0x45, levels[level])
+	qrFunction(0x50, append([]byte{0x30}, payload...)...)
+	qrFunction(0x51, 0x30)
+}
+
+// writeESCPOSRaster writes img as a GS v 0 raster bit image, one bit per dot, most significant bit first.
+func writeESCPOSRaster(buf *bytes.Buffer, img *image.Paletted) {
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+	widthBytes := (width + 7) / 8
+	buf.Write([]byte{0x1D, 0x76, 0x30, 0x00, byte(widthBytes), byte(widthBytes >> 8), byte(height), byte(height >> 8)})
+
+	row := make([]byte, widthBytes)
+	for y := 0; y < height; y++ {
+		clear(row)
+		for x := 0; x < width; x++ {
+			if img.ColorIndexAt(img.Rect.Min.X+x, img.Rect.Min.Y+y) == 1 {
+				row[x/8] |= 0x80 >> (x % 8)
+			}
+		}
+		buf.Write(row)
+	}
+}
+
+// toTIS620 encodes s as TIS-620, the ASCII compatible Thai encoding of receipt printers. Other
+// characters are printed as '?'.
+func toTIS620(s string) []byte {
+	encoded := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r < 0x80:
+			encoded = append(encoded, byte(r))
+		case r >= 0x0E01 && r <= 0x0E5B:
+			encoded = append(encoded, byte(r-0x0E00+0xA0))
+		default:
+			encoded = append(encoded, '?')
+		}
+	}
+	return encoded
+}
@@ -0,0 +1,121 @@
+package thaiqr_test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/Jdemon/thaiqr"
+	"github.com/stretchr/testify/assert"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestWriteESCPOSGolden(t *testing.T) {
+	receipt, err := thaiqr.PromptPayESCPOSReceipt("Jdemon Shop", thaiqr.PromptPayQRCmd{
+		ProxyID:   "0909764856",
+		ProxyType: thaiqr.ProxyTypeMsisdn,
+		Amount:    "100",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Jdemon Shop", "Amount: 100.00 THB"}, receipt.Lines)
+
+	tests := []struct {
+		golden     string
+		paperWidth thaiqr.PaperWidth
+		mode       thaiqr.ESCPOSQRMode
+	}{
+		{"escpos_native_80mm.bin", thaiqr.PaperWidth80mm, thaiqr.ESCPOSQRNative},
+		{"escpos_raster_58mm.bin", thaiqr.PaperWidth58mm, thaiqr.ESCPOSQRRaster},
+	}
+	for _, test := range tests {
+		t.Run(test.golden, func(t *testing.T) {
+			receipt.PaperWidth = test.paperWidth
+			receipt.Mode = test.mode
+			receipt.Cut = true
+
+			var buf bytes.Buffer
+			err := thaiqr.WriteESCPOS(&buf, receipt, thaiqr.WithRenderOptions(thaiqr.RenderOptions{
+				ErrorCorrection: thaiqr.ErrorCorrectionMedium,
+			}))
+			assert.Nil(t, err)
+			assertGolden(t, test.golden, buf.Bytes())
+		})
+	}
+}
+
+func TestWriteESCPOSNativeCommands(t *testing.T) {
+	var buf bytes.Buffer
+	err := thaiqr.WriteESCPOS(&buf, thaiqr.ESCPOSReceipt{Payload: "ABC"}, thaiqr.WithRenderOptions(thaiqr.RenderOptions{
+		ErrorCorrection: thaiqr.ErrorCorrectionLow,
+	}))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{
+		0x1B, 0x40, // initialise
+		0x1B, 0x61, 0x01, // centre
+		0x1D, 0x28, 0x6B, 0x04, 0x00, 0x31, 0x41, 0x32, 0x00, // model 2
+		0x1D, 0x28, 0x6B, 0x03, 0x00, 0x31, 0x43, 0x0D, // 21 + 2*4 modules on 384 dots: 13 dots per module
+		0x1D, 0x28, 0x6B, 0x03, 0x00, 0x31, 0x45, 0x30, // error correction L
+		0x1D, 0x28, 0x6B, 0x06, 0x00, 0x31, 0x50, 0x30, 'A', 'B', 'C', // store
+		0x1D, 0x28, 0x6B, 0x03, 0x00, 0x31, 0x51, 0x30, // print
+		'\n',
+	}, buf.Bytes())
+}
+
+func TestWriteESCPOSThaiLines(t *testing.T) {
+	var buf bytes.Buffer
+	err := thaiqr.WriteESCPOS(&buf, thaiqr.ESCPOSReceipt{
+		Payload:      "ABC",
+		Lines:        []string{"ร้าน A €"},
+		ThaiCodePage: 26,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x1B, 0x74, 26}, buf.Bytes()[5:8])
+	assert.True(t, bytes.HasSuffix(buf.Bytes(), []byte{0xC3, 0xE9, 0xD2, 0xB9, ' ', 'A', ' ', '?', '\n'}))
+}
+
+func TestWriteESCPOSRasterIsScannable(t *testing.T) {
+	var buf bytes.Buffer
+	err := thaiqr.WriteESCPOS(&buf, thaiqr.ESCPOSReceipt{Payload: promptPayTestPayload, Mode: thaiqr.ESCPOSQRRaster})
+	assert.Nil(t, err)
+
+	// GS v 0 m xL xH yL yH follows ESC @ and ESC a 1
+	data := buf.Bytes()
+	assert.Equal(t, []byte{0x1D, 0x76, 0x30, 0x00}, data[5:9])
+	widthBytes, height := int(data[9])|int(data[10])<<8, int(data[11])|int(data[12])<<8
+	assert.LessOrEqual(t, widthBytes*8, int(thaiqr.PaperWidth58mm))
+	raster := data[13 : 13+widthBytes*height]
+
+	img := image.NewGray(image.Rect(0, 0, widthBytes*8, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < widthBytes*8; x++ {
+			if raster[y*widthBytes+x/8]&(0x80>>(x%8)) == 0 {
+				img.SetGray(x, y, color.Gray{Y: 0xff})
+			}
+		}
+	}
+	found, err := thaiqr.ReadImage(img)
+	assert.Nil(t, err)
+	assert.Len(t, found, 1)
+	assert.Equal(t, promptPayTestPayload, found[0].Payload)
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("printer offline")
+}
+
+func TestWriteESCPOSInvalid(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Error(t, thaiqr.WriteESCPOS(&buf, thaiqr.ESCPOSReceipt{Payload: "ABC", Mode: "pdf"}))
+	assert.Error(t, thaiqr.WriteESCPOS(&buf, thaiqr.ESCPOSReceipt{Payload: "ABC", PaperWidth: 20}))
+	assert.Error(t, thaiqr.WriteESCPOS(&buf, thaiqr.ESCPOSReceipt{Payload: "ABC", PaperWidth: -1}))
+	assert.Error(t, thaiqr.WriteESCPOS(failingWriter{}, thaiqr.ESCPOSReceipt{Payload: "ABC"}))
+
+	_, err := thaiqr.PromptPayESCPOSReceipt("Jdemon Shop", thaiqr.PromptPayQRCmd{
+		ProxyID:   "0909764856",
+		ProxyType: thaiqr.ProxyTypeMsisdn,
+		Amount:    "abc",
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,430 @@
+package thaiqr
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/Jdemon/thaiqr/tlv"
+	"html/template"
+	"strconv"
+	"strings"
+)
+
+// Language selects the language used when rendering an Explanation.
+type Language string
+
+const (
+	LanguageEnglish Language = "en"
+	LanguageThai    Language = "th"
+)
+
+// ExplainNode describes a single TLV field of a payload.
+type ExplainNode struct {
+	Path      string        `json:"path"`
+	ID        string        `json:"id"`
+	Offset    int           `json:"offset"`
+	Length    int           `json:"length"`
+	Value     string        `json:"value"`
+	Name      string        `json:"name"`
+	NameTH    string        `json:"nameTh"`
+	Meaning   string        `json:"meaning,omitempty"`
+	MeaningTH string        `json:"meaningTh,omitempty"`
+	Children  []ExplainNode `json:"children,omitempty"`
+}
+
+// Explanation is a human readable breakdown of a payload.
+type Explanation struct {
+	Type          QRType        `json:"type"`
+	ChecksumValid bool          `json:"checksumValid"`
+	Nodes         []ExplainNode `json:"nodes"`
+}
+
+type tagInfo struct {
+	name   string
+	nameTH string
+}
+
+var mpmTagNames = map[string]tagInfo{
+	IDPayloadFormat:                     {"Payload Format Indicator", "รูปแบบข้อมูล"},
+	IDPOIMethod:                         {"Point of Initiation Method", "ประเภท QR"},
+	IDMerchantInformationBOT:            {"Merchant Account Information (PromptPay Credit Transfer)", "ข้อมูลพร้อมเพย์ (โอนเงิน)"},
+	IDMerchantInformationBOTBillPayment: {"Merchant Account Information (PromptPay Bill Payment)", "ข้อมูลพร้อมเพย์ (ชำระบิล)"},
+	IDMerchantCategoryCode:              {"Merchant Category Code", "รหัสประเภทร้านค้า"},
+	IDTransactionCurrency:               {"Transaction Currency", "สกุลเงิน"},
+	IDTransactionAmount:                 {"Transaction Amount", "จำนวนเงิน"},
+	"55":                                {"Tip or Convenience Indicator", "ตัวบ่งชี้ค่าทิปหรือค่าธรรมเนียม"},
+	"56":                                {"Value of Convenience Fee Fixed", "ค่าธรรมเนียมแบบคงที่"},
+	"57":                                {"Value of Convenience Fee Percentage", "ค่าธรรมเนียมแบบร้อยละ"},
+	IDCountryCode:                       {"Country Code", "รหัสประเทศ"},
+	IDMerchantName:                      {"Merchant Name", "ชื่อร้านค้า"},
+	IDMerchantCity:                      {"Merchant City", "เมือง"},
+	IDPostalCode:                        {"Postal Code", "รหัสไปรษณีย์"},
+	IDAdditionalFields:                  {"Additional Data Field Template", "ข้อมูลเพิ่มเติม"},
+	IDCRC:                               {"CRC", "ค่าตรวจสอบ (CRC)"},
+	"64":                                {"Merchant Information Language Template", "ข้อมูลร้านค้าภาษาอื่น"},
+}
+
+var mpmTemplateTagNames = map[string]map[string]tagInfo{
+	IDMerchantInformationBOT: {
+		BOTIDCreditTransferAID:   {"Application ID", "รหัสแอปพลิเคชัน"},
+		BOTIDMerchantMSISDN:      {"MSISDN", "หมายเลขโทรศัพท์"},
+		BOTIDMerchantNationalID:  {"National ID / Tax ID", "เลขประจำตัวประชาชน / เลขประจำตัวผู้เสียภาษี"},
+		BOTIDMerchantEWalletID:   {"E-Wallet ID", "หมายเลขกระเป๋าเงินอิเล็กทรอนิกส์"},
+		BOTIDMerchantBankAccount: {"Bank Account", "บัญชีธนาคาร"},
+		BOTIDMerchantOTA:         {"One Time Authorization", "รหัสยืนยันครั้งเดียว"},
+	},
+	IDMerchantInformationBOTBillPayment: {
+		BOTIDBillPaymentAID:      {"Application ID", "รหัสแอปพลิเคชัน"},
+		BOTIDBillPaymentBillerID: {"Biller ID", "รหัสผู้เรียกเก็บเงิน"},
+		BOTIDBillPaymentRef1:     {"Reference 1", "หมายเลขอ้างอิง 1"},
+		BOTIDBillPaymentRef2:     {"Reference 2", "หมายเลขอ้างอิง 2"},
+	},
+	IDAdditionalFields: {
+		"01":                 {"Bill Number", "เลขที่บิล"},
+		"02":                 {"Mobile Number", "หมายเลขโทรศัพท์"},
+		"03":                 {"Store Label", "รหัสร้านค้า"},
+		"04":                 {"Loyalty Number", "หมายเลขสมาชิก"},
+		"05":                 {"Reference Label", "หมายเลขอ้างอิง"},
+		"06":                 {"Customer Label", "รหัสลูกค้า"},
+		BOTIDTag62TerminalID: {"Terminal Label", "รหัสเครื่อง"},
+		"08":                 {"Purpose of Transaction", "วัตถุประสงค์ของรายการ"},
+		"09":                 {"Additional Consumer Data Request", "ข้อมูลผู้ซื้อที่ต้องการเพิ่มเติม"},
+	},
+}
+
+var verifyPaySlipTagNames = map[string]tagInfo{
+	IDQrVerifyPayload:     {"Payload", "ข้อมูลสลิป"},
+	IDQrVerifyCountryCode: {"Country Code", "รหัสประเทศ"},
+	IDQrVerifyCRC:         {"CRC", "ค่าตรวจสอบ (CRC)"},
+}
+
+var verifyPaySlipPayloadTagNames = map[string]tagInfo{
+	IDPayloadAPIID:          {"API ID", "รหัส API"},
+	IDPayloadSendingBankID:  {"Sending Bank ID", "รหัสธนาคารผู้โอน"},
+	IDPayloadTransactionRef: {"Transaction Reference", "เลขที่อ้างอิงรายการ"},
+}
+
+var countryNames = map[string]tagInfo{
+	"BN":          {"Brunei", "บรูไน"},
+	"ID":          {"Indonesia", "อินโดนีเซีย"},
+	"KH":          {"Cambodia", "กัมพูชา"},
+	CountryCodeLA: {"Laos", "ลาว"},
+	"MM":          {"Myanmar", "เมียนมา"},
+	"MY":          {"Malaysia", "มาเลเซีย"},
+	"PH":          {"Philippines", "ฟิลิปปินส์"},
+	"SG":          {"Singapore", "สิงคโปร์"},
+	CountryCodeTH: {"Thailand", "ไทย"},
+	"VN":          {"Vietnam", "เวียดนาม"},
+}
+
+var thaiBankNames = map[string]string{
+	"002": "BBL",
+	"004": "KBANK",
+	"006": "KTB",
+	"011": "TTB",
+	"014": "SCB",
+	"025": "BAY",
+	"030": "GSB",
+	"033": "GHB",
+	"034": "BAAC",
+	"069": "KKP",
+	"073": "LHBANK",
+}
+
+// Explain breaks a payload down into a tree of named fields with their offsets and decoded meaning.
+//
+// Unlike the readers, Explain does not reject a payload with an invalid checksum so that broken
+// QR codes can still be inspected; ChecksumValid reports the result instead.
+func Explain(data string) (*Explanation, error) {
+	qrType := DetectQRType(data)
+
+	names := mpmTagNames
+	if qrType == QRTypeVerifyPaySlip {
+		names = verifyPaySlipTagNames
+	}
+
+	checksumValid := VerifyPayloadChecksum(data)
+	nodes, err := explainSegments(data, "", 0, func(id string) tagInfo {
+		return lookupTagInfo(qrType, names, id)
+	}, func(node *ExplainNode) {
+		describeTopLevel(qrType, node, checksumValid)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Explanation{
+		Type:          qrType,
+		ChecksumValid: checksumValid,
+		Nodes:         nodes,
+	}, nil
+}
+
+// explainSegments deserializes data into nodes, resolving names and meanings through the given callbacks.
+func explainSegments(data, parentPath string, offset int, name func(id string) tagInfo, describe func(node *ExplainNode)) ([]ExplainNode, error) {
+	nodes := make([]ExplainNode, 0)
+	decoder := tlv.NewDecoder(data)
+	for decoder.Next() {
+		field := decoder.Field()
+		info := name(field.ID)
+		node := ExplainNode{
+			Path:   field.ID,
+			ID:     field.ID,
+			Offset: offset + field.Offset,
+			Length: field.Length,
+			Value:  field.Value,
+			Name:   info.name,
+			NameTH: info.nameTH,
+		}
+		if parentPath != "" {
+			node.Path = parentPath + "." + field.ID
+		}
+		describe(&node)
+		nodes = append(nodes, node)
+	}
+	if err := decoder.Err(); err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, invalidFormat()
+	}
+
+	return nodes, nil
+}
+
+func lookupTagInfo(qrType QRType, names map[string]tagInfo, id string) tagInfo {
+	if info, ok := names[id]; ok && (qrType == QRTypePromptPay || !isPromptPayTemplateID(id)) {
+		return info
+	}
+	if qrType == QRTypeVerifyPaySlip {
+		return tagInfo{"Unknown", "ไม่ทราบ"}
+	}
+	if n, err := strconv.Atoi(id); err == nil {
+		switch {
+		case n >= 2 && n <= 51:
+			return tagInfo{"Merchant Account Information", "ข้อมูลบัญชีร้านค้า"}
+		case n >= 65 && n <= 79:
+			return tagInfo{"RFU for EMVCo", "สงวนไว้สำหรับ EMVCo"}
+		case n >= 80 && n <= 99:
+			return tagInfo{"Unreserved Template", "ข้อมูลเฉพาะของผู้ให้บริการ"}
+		}
+	}
+	return tagInfo{"Unknown", "ไม่ทราบ"}
+}
+
+// isTemplateID reports whether a top level ID holds a nested TLV template.
+func isTemplateID(qrType QRType, id string) bool {
+	if qrType == QRTypeVerifyPaySlip {
+		return id == IDQrVerifyPayload
+	}
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return false
+	}
+	return (n >= 26 && n <= 51) || n == 62 || n == 64 || (n >= 80 && n <= 99)
+}
+
+// isPromptPayTemplateID reports whether id is one of the merchant account tags PromptPay assigns its own meaning to.
+func isPromptPayTemplateID(id string) bool {
+	return id == IDMerchantInformationBOT || id == IDMerchantInformationBOTBillPayment
+}
+
+func describeTopLevel(qrType QRType, node *ExplainNode, checksumValid bool) {
+	if isTemplateID(qrType, node.ID) {
+		var subNames map[string]tagInfo
+		if qrType == QRTypePromptPay || !isPromptPayTemplateID(node.ID) {
+			subNames = mpmTemplateTagNames[node.ID]
+		}
+		if qrType == QRTypeVerifyPaySlip {
+			subNames = verifyPaySlipPayloadTagNames
+		}
+		children, err := explainSegments(node.Value, node.Path, node.Offset+4, func(id string) tagInfo {
+			if info, ok := subNames[id]; ok {
+				return info
+			}
+			if id == "00" {
+				return tagInfo{"Globally Unique Identifier", "รหัสอ้างอิงสากล"}
+			}
+			return tagInfo{"Payment Network Specific", "ข้อมูลเฉพาะของเครือข่าย"}
+		}, func(child *ExplainNode) {
+			describeTemplateField(qrType, child)
+		})
+		if err == nil {
+			node.Children = children
+		}
+		return
+	}
+
+	if qrType == QRTypeVerifyPaySlip {
+		switch node.ID {
+		case IDQrVerifyCountryCode:
+			describeCountry(node)
+		case IDQrVerifyCRC:
+			describeChecksum(node, checksumValid)
+		}
+		return
+	}
+
+	switch node.ID {
+	case IDPayloadFormat:
+		if node.Value == PayloadFormatEMVQRCPSMerchantPresentedMode {
+			node.Meaning, node.MeaningTH = "EMV merchant presented mode", "QR แสดงโดยร้านค้า (EMV)"
+		}
+	case IDPOIMethod:
+		switch node.Value {
+		case POIMethodStatic:
+			node.Meaning, node.MeaningTH = "Static (reusable)", "แบบคงที่ (ใช้ซ้ำได้)"
+		case POIMethodDynamic:
+			node.Meaning, node.MeaningTH = "Dynamic (single use)", "แบบไดนามิก (ใช้ครั้งเดียว)"
+		}
+	case IDTransactionCurrency:
+		if code := GetCurrencyCode(node.Value); code != "" {
+			node.Meaning, node.MeaningTH = code, code
+		}
+	case IDCountryCode:
+		describeCountry(node)
+	case IDCRC:
+		describeChecksum(node, checksumValid)
+	}
+}
+
+func describeTemplateField(qrType QRType, node *ExplainNode) {
+	if qrType != QRTypePromptPay && qrType != QRTypeVerifyPaySlip && isPromptPayTemplateID(node.Path[:2]) {
+		return
+	}
+	switch node.Path {
+	case IDMerchantInformationBOT + "." + BOTIDCreditTransferAID,
+		IDMerchantInformationBOTBillPayment + "." + BOTIDBillPaymentAID:
+		switch node.Value {
+		case GUIDPromptPay:
+			node.Meaning, node.MeaningTH = "PromptPay credit transfer", "พร้อมเพย์ โอนเงิน"
+		case GUIDPromptPayBillPayment:
+			node.Meaning, node.MeaningTH = "PromptPay bill payment", "พร้อมเพย์ ชำระบิล"
+		case GUIDPromptPayBillPaymentCrossBorder:
+			node.Meaning, node.MeaningTH = "PromptPay cross-border bill payment", "พร้อมเพย์ ชำระบิลข้ามประเทศ"
+		}
+	case IDMerchantInformationBOT + "." + BOTIDMerchantMSISDN:
+		if msisdn := formatMSISDN(node.Value); msisdn != "" {
+			node.Meaning, node.MeaningTH = msisdn, msisdn
+		}
+	case IDMerchantInformationBOT + "." + BOTIDMerchantNationalID:
+		if len(node.Value) == 13 {
+			v := node.Value
+			id := fmt.Sprintf("%s-%s-%s-%s-%s", v[:1], v[1:5], v[5:10], v[10:12], v[12:])
+			node.Meaning, node.MeaningTH = id, id
+		}
+	case IDQrVerifyPayload + "." + IDPayloadSendingBankID:
+		if bank := thaiBankNames[node.Value]; bank != "" {
+			node.Meaning, node.MeaningTH = bank, bank
+		}
+	}
+}
+
+func describeCountry(node *ExplainNode) {
+	if country, ok := countryNames[node.Value]; ok {
+		node.Meaning, node.MeaningTH = country.name, country.nameTH
+	}
+}
+
+func describeChecksum(node *ExplainNode, checksumValid bool) {
+	if checksumValid {
+		node.Meaning, node.MeaningTH = "valid", "ถูกต้อง"
+		return
+	}
+	node.Meaning, node.MeaningTH = "invalid", "ไม่ถูกต้อง"
+}
+
+// formatMSISDN converts a 13 digit PromptPay MSISDN (0066812345678) into a local number (081-234-5678).
+func formatMSISDN(value string) string {
+	if len(value) != 13 || !strings.HasPrefix(value, "0066") {
+		return ""
+	}
+	local := "0" + value[4:]
+	return fmt.Sprintf("%s-%s-%s", local[:3], local[3:6], local[6:])
+}
+
+// Text renders the explanation as an indented tree.
+func (e *Explanation) Text(lang Language) string {
+	var sb strings.Builder
+	writeExplainNodes(&sb, e.Nodes, 0, lang)
+	return sb.String()
+}
+
+func writeExplainNodes(sb *strings.Builder, nodes []ExplainNode, depth int, lang Language) {
+	for _, node := range nodes {
+		name, meaning := node.localized(lang)
+		sb.WriteString(strings.Repeat("  ", depth))
+		if len(node.Children) > 0 {
+			_, _ = fmt.Fprintf(sb, "[%03d] %s %s (%d)\n", node.Offset, node.Path, name, node.Length)
+			writeExplainNodes(sb, node.Children, depth+1, lang)
+			continue
+		}
+		_, _ = fmt.Fprintf(sb, "[%03d] %s %s (%d): %s", node.Offset, node.Path, name, node.Length, node.Value)
+		if meaning != "" {
+			sb.WriteString(" → " + meaning)
+		}
+		sb.WriteString("\n")
+	}
+}
+
+func (n ExplainNode) localized(lang Language) (string, string) {
+	if lang == LanguageThai {
+		return n.NameTH, n.MeaningTH
+	}
+	return n.Name, n.Meaning
+}
+
+var explainHTMLTemplate = template.Must(template.New("explain").Funcs(template.FuncMap{
+	"view": explainHTMLData,
+}).Parse(`
+{{- define "nodes" -}}
+<ul>
+{{- range .Nodes }}
+<li><code>[{{ printf "%03d" .Offset }}]</code> <strong>{{ .Path }}</strong> {{ .Name }} ({{ .Length }})
+{{- if .Children }}{{ template "nodes" (view .Children $.Lang) }}
+{{- else }}: <code>{{ .Value }}</code>{{ if .Meaning }} &rarr; {{ .Meaning }}{{ end }}{{ end -}}
+</li>
+{{- end }}
+</ul>
+{{- end -}}
+<div class="thaiqr-explain">{{ template "nodes" . }}</div>`))
+
+type explainHTMLNode struct {
+	Path     string
+	Offset   int
+	Length   int
+	Value    string
+	Name     string
+	Meaning  string
+	Children []ExplainNode
+}
+
+// HTML renders the explanation as a nested HTML list.
+func (e *Explanation) HTML(lang Language) (string, error) {
+	var buf bytes.Buffer
+	if err := explainHTMLTemplate.Execute(&buf, explainHTMLData(e.Nodes, lang)); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+type explainHTMLView struct {
+	Nodes []explainHTMLNode
+	Lang  Language
+}
+
+func explainHTMLData(nodes []ExplainNode, lang Language) explainHTMLView {
+	view := explainHTMLView{Lang: lang}
+	for _, node := range nodes {
+		name, meaning := node.localized(lang)
+		view.Nodes = append(view.Nodes, explainHTMLNode{
+			Path:     node.Path,
+			Offset:   node.Offset,
+			Length:   node.Length,
+			Value:    node.Value,
+			Name:     name,
+			Meaning:  meaning,
+			Children: node.Children,
+		})
+	}
+	return view
+}
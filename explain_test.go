@@ -0,0 +1,69 @@
+package thaiqr_test
+
+import (
+	"github.com/Jdemon/thaiqr"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestExplainPromptPay(t *testing.T) {
+	explanation, err := thaiqr.Explain("00020101021229370016A0000006770101110113006690976485653037645802TH540510.006304CF65")
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.QRTypePromptPay, explanation.Type)
+	assert.True(t, explanation.ChecksumValid)
+	assert.Len(t, explanation.Nodes, 7)
+
+	merchant := explanation.Nodes[2]
+	assert.Equal(t, "29", merchant.Path)
+	assert.Equal(t, 12, merchant.Offset)
+	assert.Len(t, merchant.Children, 2)
+
+	msisdn := merchant.Children[1]
+	assert.Equal(t, "29.01", msisdn.Path)
+	assert.Equal(t, 36, msisdn.Offset)
+	assert.Equal(t, "MSISDN", msisdn.Name)
+	assert.Equal(t, "090-976-4856", msisdn.Meaning)
+
+	assert.Equal(t, "THB", explanation.Nodes[3].Meaning)
+	assert.Equal(t, "ไทย", explanation.Nodes[4].MeaningTH)
+
+	text := explanation.Text(thaiqr.LanguageEnglish)
+	assert.Contains(t, text, "[036] 29.01 MSISDN (13): 0066909764856 → 090-976-4856")
+	assert.Contains(t, text, "[053] 53 Transaction Currency (3): 764 → THB")
+	assert.Contains(t, explanation.Text(thaiqr.LanguageThai), "29.01 หมายเลขโทรศัพท์ (13)")
+
+	html, err := explanation.HTML(thaiqr.LanguageEnglish)
+	assert.Nil(t, err)
+	assert.Contains(t, html, "<strong>29.01</strong> MSISDN (13): <code>0066909764856</code> &rarr; 090-976-4856")
+}
+
+func TestExplainVerifyPaySlip(t *testing.T) {
+	explanation, err := thaiqr.Explain("003700060000010103006021620231130773524225102TH9104EC49")
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.QRTypeVerifyPaySlip, explanation.Type)
+
+	payload := explanation.Nodes[0]
+	assert.Len(t, payload.Children, 3)
+	assert.Equal(t, "00.01", payload.Children[1].Path)
+	assert.Equal(t, "Sending Bank ID", payload.Children[1].Name)
+	assert.Equal(t, "KTB", payload.Children[1].Meaning)
+}
+
+func TestExplainInvalidChecksum(t *testing.T) {
+	explanation, err := thaiqr.Explain("00020101021229370016A0000006770101110113006690976485653037645802TH540510.006304CF66")
+	assert.Nil(t, err)
+	assert.False(t, explanation.ChecksumValid)
+	assert.Equal(t, "invalid", explanation.Nodes[len(explanation.Nodes)-1].Meaning)
+}
+
+func TestExplainMalformed(t *testing.T) {
+	_, err := thaiqr.Explain("000201010212299")
+	assert.Error(t, err)
+}
+
+func TestExplainShortInput(t *testing.T) {
+	for _, data := range []string{"", "a", "ab", "abc"} {
+		_, err := thaiqr.Explain(data)
+		assert.Error(t, err, data)
+	}
+}
@@ -0,0 +1,26 @@
+package thaiqr
+
+import (
+	_ "embed"
+	"golang.org/x/image/font/opentype"
+	"sync"
+)
+
+// The Noto Sans Thai font covers Thai and Latin script and is licensed under the SIL Open
+// Font License, see assets/fonts/OFL.txt. Posters and PDF captions are set in it.
+//
+//go:embed assets/fonts/NotoSansThai-Regular.ttf
+var thaiFontTTF []byte
+
+var (
+	thaiFontOnce sync.Once
+	thaiFont     *opentype.Font
+	thaiFontErr  error
+)
+
+func loadThaiFont() (*opentype.Font, error) {
+	thaiFontOnce.Do(func() {
+		thaiFont, thaiFontErr = opentype.Parse(thaiFontTTF)
+	})
+	return thaiFont, thaiFontErr
+}
@@ -0,0 +1,174 @@
+package thaiqr
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"image/png"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+)
+
+// mimeLineLength is the longest base64 line allowed in a MIME body.
+const mimeLineLength = 76
+
+var altTextNetworks = map[QRType]string{
+	QRTypePromptPay: "PromptPay",
+	QRTypePayNow:    "PayNow",
+	QRTypeDuitNow:   "DuitNow",
+	QRTypeQRIS:      "QRIS",
+	QRTypeVietQR:    "VietQR",
+	QRTypeKHQR:      "KHQR",
+	QRTypeLaoQR:     "LAPNet",
+	QRTypeQRPh:      "QR Ph",
+	QRTypeMMQR:      "MMQR",
+}
+
+// InlineImage is a PNG QR code ready to be inlined in a web page or an email.
+type InlineImage struct {
+	PNG []byte
+	// Alt describes the payment for screen readers, see PaymentAltText.
+	Alt    string
+	Width  int
+	Height int
+}
+
+// NewInlineImage wraps the PNG generated for payload by GenerateQR or GenerateQRWithThaiQRLogo.
+func NewInlineImage(payload string, pngData []byte) (InlineImage, error) {
+	config, err := png.DecodeConfig(bytes.NewReader(pngData))
+	if err != nil {
+		return InlineImage{}, err
+	}
+	return InlineImage{PNG: pngData, Alt: PaymentAltText(payload), Width: config.Width, Height: config.Height}, nil
+}
+
+// DataURI returns the image as a data:image/png;base64 URI.
+func (i InlineImage) DataURI() string {
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(i.PNG)
+}
+
+// HTML returns an <img> element embedding the image as a data URI.
+func (i InlineImage) HTML() string {
+	return i.imgTag(i.DataURI())
+}
+
+// CIDHTML returns an <img> element referencing the MIME part written by WriteMIMEPart with the same contentID.
+func (i InlineImage) CIDHTML(contentID string) (string, error) {
+	if err := validateContentID(contentID); err != nil {
+		return "", err
+	}
+	return i.imgTag("cid:" + contentID), nil
+}
+
+func (i InlineImage) imgTag(src string) string {
+	return fmt.Sprintf(`<img src="%s" alt="%s" width="%d" height="%d">`, html.EscapeString(src), html.EscapeString(i.Alt), i.Width, i.Height)
+}
+
+// MIMEHeader returns the headers of an inline image/png part identified by contentID, e.g. "qr@example.com".
+func (i InlineImage) MIMEHeader(contentID string) (textproto.MIMEHeader, error) {
+	if err := validateContentID(contentID); err != nil {
+		return nil, err
+	}
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Type", `image/png; name="qr.png"`)
+	header.Set("Content-Transfer-Encoding", "base64")
+	header.Set("Content-ID", "<"+contentID+">")
+	header.Set("Content-Disposition", `inline; filename="qr.png"`)
+	return header, nil
+}
+
+// WriteMIMEPart adds the image to a multipart/related email body as an inline part identified by contentID,
+// to be referenced from the HTML part with CIDHTML.
+func (i InlineImage) WriteMIMEPart(w *multipart.Writer, contentID string) error {
+	header, err := i.MIMEHeader(contentID)
+	if err != nil {
+		return err
+	}
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return err
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(i.PNG)
+	var body strings.Builder
+	for len(encoded) > mimeLineLength {
+		body.WriteString(encoded[:mimeLineLength] + "\r\n")
+		encoded = encoded[mimeLineLength:]
+	}
+	body.WriteString(encoded + "\r\n")
+	_, err = part.Write([]byte(body.String()))
+	return err
+}
+
+// validateContentID checks contentID can be used both between angle brackets and in a cid: URL.
+func validateContentID(contentID string) error {
+	if contentID == "" || strings.ContainsAny(contentID, "<>\"%' \t\r\n") {
+		return errors.New("invalid content id")
+	}
+	return nil
+}
+
+// PaymentAltText describes a payload in plain English for the alt text of its QR image, e.g.
+// "PromptPay QR code: pay 090-976-4856, 100.00 THB". Unreadable payloads are described as "QR code".
+func PaymentAltText(payload string) string {
+	qrType := DetectQRType(payload)
+	switch qrType {
+	case QRTypeUnknown:
+		return "QR code"
+	case QRTypeVerifyPaySlip:
+		return "Slip verification QR code"
+	}
+
+	// Every merchant presented payload shares the root fields decoded by the PromptPay reader.
+	result, err := NewPromptPayQR().Reader(payload)
+	if err != nil {
+		return "QR code"
+	}
+
+	network, ok := altTextNetworks[qrType]
+	if !ok {
+		network = "Payment"
+	}
+	text := network + " QR code"
+	if payee := altTextPayee(result); payee != "" {
+		text += ": pay " + payee
+	}
+	if result.TransactionAmount != "" {
+		text += ", " + result.TransactionAmount
+		if result.TransactionCurrencyCode != "" {
+			text += " " + result.TransactionCurrencyCode
+		}
+	}
+	return text
+}
+
+// altTextPayee names the payee by merchant name or, for PromptPay, by the proxy. Only the last
+// digits of ID and account numbers are given.
+func altTextPayee(result *PromptPayQRResults) string {
+	if result.MerchantName != "" {
+		return result.MerchantName
+	}
+	if ct := result.CreditTransfer; ct != nil {
+		switch {
+		case ct.MSISDN != "":
+			return ifThenElse(formatMSISDN(ct.MSISDN) != "", formatMSISDN(ct.MSISDN), ct.MSISDN).(string)
+		case ct.NationalID != "":
+			return "national ID ending " + lastDigits(ct.NationalID)
+		case ct.EWalletID != "":
+			return "e-wallet ending " + lastDigits(ct.EWalletID)
+		case ct.BankAccount != "":
+			return "bank account ending " + lastDigits(ct.BankAccount)
+		}
+	}
+	if bp := result.BillPayment; bp != nil && bp.BillerID != "" {
+		return "biller " + bp.BillerID
+	}
+	return ""
+}
+
+func lastDigits(value string) string {
+	return value[max(len(value)-4, 0):]
+}
@@ -0,0 +1,106 @@
+package thaiqr_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"github.com/Jdemon/thaiqr"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"mime/multipart"
+	"strings"
+	"testing"
+)
+
+func TestPaymentAltText(t *testing.T) {
+	msisdnPayload, err := thaiqr.NewPromptPayQR().GeneratePayload(thaiqr.PromptPayQRCmd{
+		ProxyID:   "0909764856",
+		ProxyType: thaiqr.ProxyTypeMsisdn,
+		Amount:    "100",
+	})
+	assert.Nil(t, err)
+	billPayload, err := thaiqr.NewPromptPayQR().GenerateBillPaymentPayload(thaiqr.PromptPayBillPaymentQRCmd{
+		BillerID: "0105556123456",
+		Ref1:     "INV001",
+	})
+	assert.Nil(t, err)
+	qrPhPayload, err := thaiqr.NewQRPh().GeneratePayload(thaiqr.QRPhCmd{
+		Type:         thaiqr.QRPhTypeP2M,
+		AcquirerID:   "BNORPHMMXXX",
+		MerchantID:   "MID0001",
+		MerchantName: "SARI SARI STORE",
+		Amount:       "250",
+	})
+	assert.Nil(t, err)
+
+	tests := []struct {
+		payload  string
+		expected string
+	}{
+		{msisdnPayload, "PromptPay QR code: pay 090-976-4856, 100.00 THB"},
+		{promptPayTestPayload, "PromptPay QR code: pay e-wallet ending 0076, 10.00 THB"},
+		{billPayload, "PromptPay QR code: pay biller 0105556123456"},
+		{qrPhPayload, "QR Ph QR code: pay SARI SARI STORE, 250.00 PHP"},
+		{slipTestPayload, "Slip verification QR code"},
+		{"https://example.com", "QR code"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, thaiqr.PaymentAltText(test.payload))
+	}
+}
+
+func TestInlineImageHTML(t *testing.T) {
+	qrBytes, err := thaiqr.GenerateQRWithThaiQRLogo(promptPayTestPayload, thaiqr.WithRenderOptions(thaiqr.RenderOptions{Size: 256}))
+	assert.Nil(t, err)
+
+	img, err := thaiqr.NewInlineImage(promptPayTestPayload, *qrBytes)
+	assert.Nil(t, err)
+	assert.Equal(t, 256, img.Width)
+
+	uri := img.DataURI()
+	assert.True(t, strings.HasPrefix(uri, "data:image/png;base64,"))
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(uri, "data:image/png;base64,"))
+	assert.Nil(t, err)
+	assert.Equal(t, *qrBytes, decoded)
+
+	assert.Equal(t, `<img src="`+uri+`" alt="PromptPay QR code: pay e-wallet ending 0076, 10.00 THB" width="256" height="256">`, img.HTML())
+
+	img.Alt = `Tom & Jerry "Shop"`
+	tag, err := img.CIDHTML("qr@example.com")
+	assert.Nil(t, err)
+	assert.Equal(t, `<img src="cid:qr@example.com" alt="Tom &amp; Jerry &#34;Shop&#34;" width="256" height="256">`, tag)
+
+	_, err = thaiqr.NewInlineImage(promptPayTestPayload, []byte("not a png"))
+	assert.Error(t, err)
+}
+
+func TestInlineImageMIMEPart(t *testing.T) {
+	qrBytes, err := thaiqr.GenerateQR(promptPayTestPayload)
+	assert.Nil(t, err)
+	img, err := thaiqr.NewInlineImage(promptPayTestPayload, *qrBytes)
+	assert.Nil(t, err)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	assert.Nil(t, img.WriteMIMEPart(writer, "qr@example.com"))
+	assert.Nil(t, writer.Close())
+
+	part, err := multipart.NewReader(&body, writer.Boundary()).NextPart()
+	assert.Nil(t, err)
+	assert.Equal(t, "<qr@example.com>", part.Header.Get("Content-ID"))
+	assert.Equal(t, `image/png; name="qr.png"`, part.Header.Get("Content-Type"))
+	assert.Equal(t, "base64", part.Header.Get("Content-Transfer-Encoding"))
+	assert.Equal(t, `inline; filename="qr.png"`, part.Header.Get("Content-Disposition"))
+
+	encoded, err := io.ReadAll(part)
+	assert.Nil(t, err)
+	for _, line := range strings.Split(strings.TrimSuffix(string(encoded), "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 76)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(encoded), "\r\n", ""))
+	assert.Nil(t, err)
+	assert.Equal(t, *qrBytes, decoded)
+
+	assert.Error(t, img.WriteMIMEPart(writer, "<qr@example.com>"))
+	_, err = img.CIDHTML("")
+	assert.Error(t, err)
+}
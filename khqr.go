@@ -0,0 +1,350 @@
+package thaiqr
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// KHQRIDBakongAccountID Individual Tag 29 and Merchant Tag 30
+	KHQRIDBakongAccountID    = "00"
+	KHQRIDAccountInformation = "01"
+	KHQRIDMerchantID         = "01"
+	KHQRIDAcquiringBank      = "02"
+
+	// KHQRIDCreationTimestamp Timestamp Tag 99
+	KHQRIDCreationTimestamp   = "00"
+	KHQRIDExpirationTimestamp = "01"
+
+	KHQRIDTag62BillNumber    = "01"
+	KHQRIDTag62MobileNumber  = "02"
+	KHQRIDTag62StoreLabel    = "03"
+	KHQRIDTag62TerminalLabel = "07"
+
+	IDMerchantInformationKHQRIndividual = "29"
+	IDMerchantInformationKHQRMerchant   = "30"
+	IDKHQRTimestamp                     = "99"
+
+	TransactionCurrencyKHR = "116"
+	TransactionCurrencyUSD = "840"
+	CountryCodeKH          = "KH"
+
+	KHQRAccountTypeIndividual = "INDIVIDUAL"
+	KHQRAccountTypeMerchant   = "MERCHANT"
+)
+
+// khqrCurrencies are the currencies a KHQR can be issued in. USD is kept out of the shared
+// currency table so other generators cannot emit it.
+var khqrCurrencies = map[string]string{
+	"KHR": TransactionCurrencyKHR,
+	"USD": TransactionCurrencyUSD,
+}
+
+type KHQRCmd struct {
+	AccountType          string    `json:"accountType"`
+	BakongAccountID      string    `json:"bakongAccountId"`
+	AccountInformation   string    `json:"accountInformation"`
+	MerchantID           string    `json:"merchantId"`
+	AcquiringBank        string    `json:"acquiringBank"`
+	CurrencyCode         string    `json:"currencyCode"`
+	Amount               string    `json:"amount"`
+	MerchantCategoryCode string    `json:"merchantCategoryCode"`
+	MerchantName         string    `json:"merchantName"`
+	MerchantCity         string    `json:"merchantCity"`
+	BillNumber           string    `json:"billNumber"`
+	MobileNumber         string    `json:"mobileNumber"`
+	StoreLabel           string    `json:"storeLabel"`
+	TerminalLabel        string    `json:"terminalLabel"`
+	CreatedAt            time.Time `json:"createdAt"`
+	ExpiresAt            time.Time `json:"expiresAt"`
+}
+
+type KHQRResults struct {
+	PayloadFormatIndicator  string     `json:"payloadFormatIndicator"`
+	PointOfInitiationMethod string     `json:"pointOfInitiationMethod"`
+	AccountType             string     `json:"accountType"`
+	BakongAccountID         string     `json:"bakongAccountId"`
+	AccountInformation      string     `json:"accountInformation,omitempty"`
+	MerchantID              string     `json:"merchantId,omitempty"`
+	AcquiringBank           string     `json:"acquiringBank,omitempty"`
+	MerchantCategoryCode    string     `json:"merchantCategoryCode,omitempty"`
+	TransactionCurrency     string     `json:"transactionCurrency"`
+	TransactionCurrencyCode string     `json:"transactionCurrencyCode"`
+	TransactionAmount       string     `json:"transactionAmount,omitempty"`
+	CountryCode             string     `json:"countryCode"`
+	MerchantName            string     `json:"merchantName"`
+	MerchantCity            string     `json:"merchantCity,omitempty"`
+	BillNumber              string     `json:"billNumber,omitempty"`
+	MobileNumber            string     `json:"mobileNumber,omitempty"`
+	StoreLabel              string     `json:"storeLabel,omitempty"`
+	TerminalLabel           string     `json:"terminalLabel,omitempty"`
+	CreationTimestamp       string     `json:"creationTimestamp,omitempty"`
+	ExpirationTimestamp     string     `json:"expirationTimestamp,omitempty"`
+	CRC                     string     `json:"crc"`
+	Segments                *[]Segment `json:"segments,omitempty"`
+}
+
+// CreatedAt returns the creation time carried in tag 99, or the zero time when absent.
+func (r *KHQRResults) CreatedAt() time.Time {
+	return parseMillisTimestamp(r.CreationTimestamp)
+}
+
+// ExpiresAt returns the expiration time carried in tag 99, or the zero time when absent.
+func (r *KHQRResults) ExpiresAt() time.Time {
+	return parseMillisTimestamp(r.ExpirationTimestamp)
+}
+
+// Expired reports whether the QR carries an expiration time that is not after now.
+func (r *KHQRResults) Expired(now time.Time) bool {
+	expiresAt := r.ExpiresAt()
+	return !expiresAt.IsZero() && !now.Before(expiresAt)
+}
+
+// KHQR represents a Cambodia KHQR (Bakong) QR code generator.
+type KHQR struct{}
+
+// NewKHQR returns a new KHQR instance.
+func NewKHQR() *KHQR {
+	return &KHQR{}
+}
+
+var bakongAccountIDPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+$`)
+
+// GeneratePayload generates a KHQR payload for an individual or merchant Bakong account.
+func (qr *KHQR) GeneratePayload(cmd KHQRCmd) (string, error) {
+	accountType := ifThenElse(cmd.AccountType != "", strings.ToUpper(cmd.AccountType), KHQRAccountTypeIndividual).(string)
+	if accountType != KHQRAccountTypeIndividual && accountType != KHQRAccountTypeMerchant {
+		return "", errors.New("invalid account type")
+	}
+	if err := validateBakongAccountID(cmd.BakongAccountID); err != nil {
+		return "", err
+	}
+	if accountType == KHQRAccountTypeMerchant && (cmd.MerchantID == "" || cmd.AcquiringBank == "") {
+		return "", errors.New("merchant id and acquiring bank are required")
+	}
+	if strings.TrimSpace(cmd.MerchantName) == "" {
+		return "", errors.New("merchant name is required")
+	}
+
+	currency := strings.ToUpper(ifThenElse(cmd.CurrencyCode != "", cmd.CurrencyCode, "KHR").(string))
+	currencyNo, ok := khqrCurrencies[currency]
+	if !ok {
+		return "", errors.New("invalid currency")
+	}
+
+	createdAt := cmd.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	if !cmd.ExpiresAt.IsZero() && !cmd.ExpiresAt.After(createdAt) {
+		return "", errors.New("expiration must be after creation")
+	}
+
+	accountData := &payloadBuilder{}
+	var accountTag string
+	if accountType == KHQRAccountTypeMerchant {
+		accountTag = IDMerchantInformationKHQRMerchant
+		accountData.field(KHQRIDBakongAccountID, cmd.BakongAccountID)
+		accountData.field(KHQRIDMerchantID, cmd.MerchantID)
+		accountData.field(KHQRIDAcquiringBank, cmd.AcquiringBank)
+	} else {
+		accountTag = IDMerchantInformationKHQRIndividual
+		accountData.field(KHQRIDBakongAccountID, cmd.BakongAccountID)
+		if cmd.AccountInformation != "" {
+			accountData.field(KHQRIDAccountInformation, cmd.AccountInformation)
+		}
+		if cmd.AcquiringBank != "" {
+			accountData.field(KHQRIDAcquiringBank, cmd.AcquiringBank)
+		}
+	}
+
+	amount := strings.TrimSpace(cmd.Amount)
+	data := &payloadBuilder{}
+	data.field(IDPayloadFormat, PayloadFormatEMVQRCPSMerchantPresentedMode)
+	data.field(IDPOIMethod, ifThenElse(amount != "", POIMethodDynamic, POIMethodStatic).(string))
+	data.template(accountTag, accountData)
+	data.field(IDMerchantCategoryCode, ifThenElse(cmd.MerchantCategoryCode != "", cmd.MerchantCategoryCode, "5999").(string))
+	data.field(IDTransactionCurrency, currencyNo)
+	if amount != "" {
+		amountFormat, err := formatAmountWithExponent(amount, khqrCurrencyExponent(currencyNo))
+		if err != nil {
+			return "", err
+		}
+		data.field(IDTransactionAmount, amountFormat)
+	}
+	data.field(IDCountryCode, CountryCodeKH)
+	data.field(IDMerchantName, cmd.MerchantName)
+	data.field(IDMerchantCity, ifThenElse(cmd.MerchantCity != "", cmd.MerchantCity, "Phnom Penh").(string))
+
+	additionalData := &payloadBuilder{}
+	for _, field := range [][2]string{
+		{KHQRIDTag62BillNumber, cmd.BillNumber},
+		{KHQRIDTag62MobileNumber, cmd.MobileNumber},
+		{KHQRIDTag62StoreLabel, cmd.StoreLabel},
+		{KHQRIDTag62TerminalLabel, cmd.TerminalLabel},
+	} {
+		if strings.TrimSpace(field[1]) != "" {
+			additionalData.field(field[0], field[1])
+		}
+	}
+	if !additionalData.empty() {
+		data.template(IDAdditionalFields, additionalData)
+	}
+
+	timestampData := &payloadBuilder{}
+	timestampData.field(KHQRIDCreationTimestamp, strconv.FormatInt(createdAt.UnixMilli(), 10))
+	if !cmd.ExpiresAt.IsZero() {
+		timestampData.field(KHQRIDExpirationTimestamp, strconv.FormatInt(cmd.ExpiresAt.UnixMilli(), 10))
+	}
+	data.template(IDKHQRTimestamp, timestampData)
+
+	return data.withChecksum(IDCRC)
+}
+
+// Reader decodes and strictly validates a KHQR payload.
+func (qr *KHQR) Reader(data string) (*KHQRResults, error) {
+	qrFields, qrSegments, err := deserializeMerchantPresented(data)
+	if err != nil {
+		return nil, err
+	}
+
+	individualData, isIndividual := qrFields[IDMerchantInformationKHQRIndividual]
+	merchantData, isMerchant := qrFields[IDMerchantInformationKHQRMerchant]
+	if isIndividual == isMerchant {
+		return nil, errors.New("exactly one bakong account is required")
+	}
+
+	results := &KHQRResults{
+		PayloadFormatIndicator:  qrFields[IDPayloadFormat],
+		PointOfInitiationMethod: qrFields[IDPOIMethod],
+		MerchantCategoryCode:    qrFields[IDMerchantCategoryCode],
+		TransactionCurrency:     qrFields[IDTransactionCurrency],
+		TransactionCurrencyCode: khqrCurrencyCode(qrFields[IDTransactionCurrency]),
+		TransactionAmount:       qrFields[IDTransactionAmount],
+		CountryCode:             qrFields[IDCountryCode],
+		MerchantName:            qrFields[IDMerchantName],
+		MerchantCity:            qrFields[IDMerchantCity],
+		CRC:                     qrFields[IDCRC],
+		Segments:                &qrSegments,
+	}
+
+	if isMerchant {
+		accountFields, _, err := deserialize(merchantData)
+		if err != nil {
+			return nil, err
+		}
+		results.AccountType = KHQRAccountTypeMerchant
+		results.BakongAccountID = accountFields[KHQRIDBakongAccountID]
+		results.MerchantID = accountFields[KHQRIDMerchantID]
+		results.AcquiringBank = accountFields[KHQRIDAcquiringBank]
+		if results.MerchantID == "" || results.AcquiringBank == "" {
+			return nil, errors.New("merchant id and acquiring bank are required")
+		}
+	} else {
+		accountFields, _, err := deserialize(individualData)
+		if err != nil {
+			return nil, err
+		}
+		results.AccountType = KHQRAccountTypeIndividual
+		results.BakongAccountID = accountFields[KHQRIDBakongAccountID]
+		results.AccountInformation = accountFields[KHQRIDAccountInformation]
+		results.AcquiringBank = accountFields[KHQRIDAcquiringBank]
+	}
+	if err := validateBakongAccountID(results.BakongAccountID); err != nil {
+		return nil, err
+	}
+
+	if results.TransactionCurrency != TransactionCurrencyKHR && results.TransactionCurrency != TransactionCurrencyUSD {
+		return nil, errors.New("invalid currency")
+	}
+	if results.TransactionAmount != "" {
+		if _, err := formatAmountWithExponent(results.TransactionAmount, khqrCurrencyExponent(results.TransactionCurrency)); err != nil {
+			return nil, err
+		}
+	}
+	if results.CountryCode != CountryCodeKH {
+		return nil, invalidFormat()
+	}
+	if results.MerchantName == "" {
+		return nil, errors.New("merchant name is required")
+	}
+
+	additionalFields, _, _ := deserialize(qrFields[IDAdditionalFields])
+	results.BillNumber = additionalFields[KHQRIDTag62BillNumber]
+	results.MobileNumber = additionalFields[KHQRIDTag62MobileNumber]
+	results.StoreLabel = additionalFields[KHQRIDTag62StoreLabel]
+	results.TerminalLabel = additionalFields[KHQRIDTag62TerminalLabel]
+
+	if timestampData, ok := qrFields[IDKHQRTimestamp]; ok {
+		timestampFields, _, err := deserialize(timestampData)
+		if err != nil {
+			return nil, err
+		}
+		results.CreationTimestamp = timestampFields[KHQRIDCreationTimestamp]
+		results.ExpirationTimestamp = timestampFields[KHQRIDExpirationTimestamp]
+		for _, timestamp := range []string{results.CreationTimestamp, results.ExpirationTimestamp} {
+			if timestamp != "" && parseMillisTimestamp(timestamp).IsZero() {
+				return nil, errors.New("invalid timestamp")
+			}
+		}
+		if results.ExpirationTimestamp != "" && !results.ExpiresAt().After(results.CreatedAt()) {
+			return nil, errors.New("expiration must be after creation")
+		}
+	}
+
+	return results, nil
+}
+
+// isKHQR reports whether top level fields look like a KHQR payload.
+func isKHQR(fields map[string]string) bool {
+	if fields[IDCountryCode] != CountryCodeKH {
+		return false
+	}
+	for _, id := range []string{IDMerchantInformationKHQRIndividual, IDMerchantInformationKHQRMerchant} {
+		accountFields, _, err := deserialize(fields[id])
+		if err == nil && bakongAccountIDPattern.MatchString(accountFields[KHQRIDBakongAccountID]) {
+			return true
+		}
+	}
+	return false
+}
+
+func validateBakongAccountID(id string) error {
+	if len(id) > 32 || !bakongAccountIDPattern.MatchString(id) {
+		return errors.New("invalid bakong account id")
+	}
+	return nil
+}
+
+// khqrCurrencyCode returns the alphabetic code of a KHQR currency number.
+func khqrCurrencyCode(currencyNo string) string {
+	for code, no := range khqrCurrencies {
+		if no == currencyNo {
+			return code
+		}
+	}
+	return ""
+}
+
+// khqrCurrencyExponent returns the number of minor unit digits KHQR uses for a currency: none for KHR, two for USD.
+func khqrCurrencyExponent(currency string) int {
+	if currency == TransactionCurrencyUSD {
+		return 2
+	}
+	return 0
+}
+
+// parseMillisTimestamp parses a 13 digit Unix millisecond timestamp, returning the zero time when invalid.
+func parseMillisTimestamp(value string) time.Time {
+	if len(value) != 13 {
+		return time.Time{}
+	}
+	millis, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.UnixMilli(millis)
+}
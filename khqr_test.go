@@ -0,0 +1,116 @@
+package thaiqr_test
+
+import (
+	"github.com/Jdemon/thaiqr"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestGenerateKHQRIndividualKHR(t *testing.T) {
+	createdAt := time.UnixMilli(1767225600000)
+	qr := thaiqr.NewKHQR()
+	cmd := thaiqr.KHQRCmd{
+		BakongAccountID: "sokha@aclb",
+		Amount:          "50000",
+		MerchantName:    "SOKHA",
+		CreatedAt:       createdAt,
+		ExpiresAt:       createdAt.Add(15 * time.Minute),
+	}
+	actualPayload, err := qr.GeneratePayload(cmd)
+	assert.Nil(t, err)
+	assert.Equal(t, "00020101021229140010sokha@aclb5204599953031165405500005802KH5905SOKHA6010Phnom Penh"+
+		"99340013176722560000001131767226500000"+"6304"+actualPayload[len(actualPayload)-4:], actualPayload)
+
+	result, err := qr.Reader(actualPayload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.KHQRAccountTypeIndividual, result.AccountType)
+	assert.Equal(t, cmd.BakongAccountID, result.BakongAccountID)
+	assert.Equal(t, "KHR", result.TransactionCurrencyCode)
+	assert.Equal(t, cmd.Amount, result.TransactionAmount)
+	assert.Equal(t, thaiqr.CountryCodeKH, result.CountryCode)
+	assert.True(t, createdAt.Equal(result.CreatedAt()))
+	assert.False(t, result.Expired(createdAt.Add(time.Minute)))
+	assert.True(t, result.Expired(createdAt.Add(15*time.Minute)))
+
+	parsed, err := thaiqr.Parse(actualPayload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.QRTypeKHQR, parsed.Type)
+	assert.Equal(t, cmd.BakongAccountID, parsed.KHQR.BakongAccountID)
+}
+
+func TestGenerateKHQRMerchantUSD(t *testing.T) {
+	qr := thaiqr.NewKHQR()
+	cmd := thaiqr.KHQRCmd{
+		AccountType:     thaiqr.KHQRAccountTypeMerchant,
+		BakongAccountID: "coffee@abaa",
+		MerchantID:      "123456",
+		AcquiringBank:   "ABA Bank",
+		CurrencyCode:    "USD",
+		Amount:          "2.50",
+		MerchantName:    "COFFEE SHOP",
+		BillNumber:      "INV-1",
+		CreatedAt:       time.UnixMilli(1767225600000),
+	}
+	actualPayload, err := qr.GeneratePayload(cmd)
+	assert.Nil(t, err)
+
+	result, err := qr.Reader(actualPayload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.KHQRAccountTypeMerchant, result.AccountType)
+	assert.Equal(t, cmd.MerchantID, result.MerchantID)
+	assert.Equal(t, cmd.AcquiringBank, result.AcquiringBank)
+	assert.Equal(t, "USD", result.TransactionCurrencyCode)
+	assert.Equal(t, "2.50", result.TransactionAmount)
+	assert.Equal(t, cmd.BillNumber, result.BillNumber)
+	assert.True(t, result.ExpiresAt().IsZero())
+	assert.False(t, result.Expired(time.Now()))
+}
+
+func TestGenerateKHQRInvalid(t *testing.T) {
+	qr := thaiqr.NewKHQR()
+	valid := thaiqr.KHQRCmd{BakongAccountID: "sokha@aclb", MerchantName: "SOKHA"}
+
+	cmd := valid
+	cmd.BakongAccountID = "sokha"
+	_, err := qr.GeneratePayload(cmd)
+	assert.Error(t, err)
+
+	cmd = valid
+	cmd.CurrencyCode = "THB"
+	_, err = qr.GeneratePayload(cmd)
+	assert.Error(t, err)
+
+	cmd = valid
+	cmd.Amount = "100.50"
+	_, err = qr.GeneratePayload(cmd)
+	assert.Error(t, err)
+
+	cmd = valid
+	cmd.AccountType = thaiqr.KHQRAccountTypeMerchant
+	_, err = qr.GeneratePayload(cmd)
+	assert.Error(t, err)
+
+	cmd = valid
+	cmd.CreatedAt = time.UnixMilli(1767225600000)
+	cmd.ExpiresAt = cmd.CreatedAt
+	_, err = qr.GeneratePayload(cmd)
+	assert.Error(t, err)
+
+	cmd = valid
+	cmd.MerchantName = ""
+	_, err = qr.GeneratePayload(cmd)
+	assert.Error(t, err)
+}
+
+func TestKHQRReaderRejectsPromptPay(t *testing.T) {
+	_, err := thaiqr.NewKHQR().Reader("00020101021229370016A0000006770101110113006690976485653037645802TH540510.006304CF65")
+	assert.Error(t, err)
+}
+
+func TestReadKHQRShortPayload(t *testing.T) {
+	for _, data := range []string{"", "ab", "abcd", "000201"} {
+		_, err := thaiqr.NewKHQR().Reader(data)
+		assert.Error(t, err, data)
+	}
+}
@@ -0,0 +1,152 @@
+package thaiqr
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+const (
+	// LaoQRIDGUID LAPNet Merchant Account Information Tag 38
+	LaoQRIDGUID         = "00"
+	LaoQRIDMemberBankID = "01"
+	LaoQRIDMerchantID   = "02"
+
+	LaoQRIDTag62BillNumber = "01"
+	LaoQRIDTag62TerminalID = "07"
+
+	IDMerchantInformationLaoQR = "38"
+	TransactionCurrencyLAK     = "418"
+)
+
+// laoMemberBankIDPattern matches a LAPNet member bank ID, the three digit ID also used as the
+// sending bank ID of Lao slip verification QRs.
+var laoMemberBankIDPattern = regexp.MustCompile(`^[0-9]{3}$`)
+
+type LaoQRCmd struct {
+	MemberBankID         string `json:"memberBankId"`
+	MerchantID           string `json:"merchantId"`
+	Amount               string `json:"amount"`
+	MerchantCategoryCode string `json:"merchantCategoryCode"`
+	MerchantName         string `json:"merchantName"`
+	MerchantCity         string `json:"merchantCity"`
+	BillNumber           string `json:"billNumber"`
+	TerminalID           string `json:"terminalId"`
+}
+
+type LaoQRResults struct {
+	PayloadFormatIndicator  string     `json:"payloadFormatIndicator"`
+	PointOfInitiationMethod string     `json:"pointOfInitiationMethod"`
+	GUID                    string     `json:"guid"`
+	MemberBankID            string     `json:"memberBankId"`
+	MerchantID              string     `json:"merchantId"`
+	MerchantCategoryCode    string     `json:"merchantCategoryCode,omitempty"`
+	TransactionCurrency     string     `json:"transactionCurrency"`
+	TransactionCurrencyCode string     `json:"transactionCurrencyCode"`
+	TransactionAmount       string     `json:"transactionAmount,omitempty"`
+	CountryCode             string     `json:"countryCode"`
+	MerchantName            string     `json:"merchantName,omitempty"`
+	MerchantCity            string     `json:"merchantCity,omitempty"`
+	BillNumber              string     `json:"billNumber,omitempty"`
+	TerminalID              string     `json:"terminalId,omitempty"`
+	CRC                     string     `json:"crc"`
+	Segments                *[]Segment `json:"segments,omitempty"`
+}
+
+// LaoQR represents a Laos LAPNet QR code generator.
+type LaoQR struct{}
+
+// NewLaoQR returns a new LaoQR instance.
+func NewLaoQR() *LaoQR {
+	return &LaoQR{}
+}
+
+// GeneratePayload generates a LAPNet QR code payload. LAK is quoted without minor units, so the amount must be a whole number.
+func (qr *LaoQR) GeneratePayload(cmd LaoQRCmd) (string, error) {
+	memberBankID := strings.TrimSpace(cmd.MemberBankID)
+	if !laoMemberBankIDPattern.MatchString(memberBankID) {
+		return "", errors.New("invalid member bank id")
+	}
+	if strings.TrimSpace(cmd.MerchantID) == "" {
+		return "", errors.New("merchant id is required")
+	}
+
+	amount := strings.TrimSpace(cmd.Amount)
+	merchantInfoData := &payloadBuilder{}
+	merchantInfoData.field(LaoQRIDGUID, GUIDLAPNet)
+	merchantInfoData.field(LaoQRIDMemberBankID, memberBankID)
+	merchantInfoData.field(LaoQRIDMerchantID, cmd.MerchantID)
+
+	data := &payloadBuilder{}
+	data.field(IDPayloadFormat, PayloadFormatEMVQRCPSMerchantPresentedMode)
+	data.field(IDPOIMethod, ifThenElse(amount != "", POIMethodDynamic, POIMethodStatic).(string))
+	data.template(IDMerchantInformationLaoQR, merchantInfoData)
+	data.field(IDMerchantCategoryCode, ifThenElse(cmd.MerchantCategoryCode != "", cmd.MerchantCategoryCode, "0000").(string))
+	data.field(IDTransactionCurrency, TransactionCurrencyLAK)
+	if amount != "" {
+		amountFormat, err := formatAmountWithExponent(amount, 0)
+		if err != nil {
+			return "", err
+		}
+		data.field(IDTransactionAmount, amountFormat)
+	}
+	data.field(IDCountryCode, CountryCodeLA)
+	data.field(IDMerchantName, ifThenElse(cmd.MerchantName != "", cmd.MerchantName, "NA").(string))
+	data.field(IDMerchantCity, ifThenElse(cmd.MerchantCity != "", cmd.MerchantCity, "Vientiane").(string))
+
+	additionalData := &payloadBuilder{}
+	if strings.TrimSpace(cmd.BillNumber) != "" {
+		additionalData.field(LaoQRIDTag62BillNumber, cmd.BillNumber)
+	}
+	if strings.TrimSpace(cmd.TerminalID) != "" {
+		additionalData.field(LaoQRIDTag62TerminalID, cmd.TerminalID)
+	}
+	if !additionalData.empty() {
+		data.template(IDAdditionalFields, additionalData)
+	}
+
+	return data.withChecksum(IDCRC)
+}
+
+// Reader decodes a LAPNet QR code payload.
+func (qr *LaoQR) Reader(data string) (*LaoQRResults, error) {
+	qrFields, qrSegments, err := deserializeMerchantPresented(data)
+	if err != nil {
+		return nil, err
+	}
+
+	_, merchantFields, _, ok := findMerchantAccount(qrSegments, GUIDLAPNet)
+	if !ok {
+		return nil, errors.New("lapnet merchant account not found")
+	}
+
+	transactionCurrency := qrFields[IDTransactionCurrency]
+	if transactionCurrency != TransactionCurrencyLAK {
+		return nil, errors.New("invalid currency")
+	}
+	countryCode := qrFields[IDCountryCode]
+	if len(countryCode) != 2 {
+		return nil, invalidFormat()
+	}
+
+	additionalFields, _, _ := deserialize(qrFields[IDAdditionalFields])
+
+	return &LaoQRResults{
+		PayloadFormatIndicator:  qrFields[IDPayloadFormat],
+		PointOfInitiationMethod: qrFields[IDPOIMethod],
+		GUID:                    merchantFields[LaoQRIDGUID],
+		MemberBankID:            merchantFields[LaoQRIDMemberBankID],
+		MerchantID:              merchantFields[LaoQRIDMerchantID],
+		MerchantCategoryCode:    qrFields[IDMerchantCategoryCode],
+		TransactionCurrency:     transactionCurrency,
+		TransactionCurrencyCode: GetCurrencyCode(transactionCurrency),
+		TransactionAmount:       qrFields[IDTransactionAmount],
+		CountryCode:             countryCode,
+		MerchantName:            qrFields[IDMerchantName],
+		MerchantCity:            qrFields[IDMerchantCity],
+		BillNumber:              additionalFields[LaoQRIDTag62BillNumber],
+		TerminalID:              additionalFields[LaoQRIDTag62TerminalID],
+		CRC:                     qrFields[IDCRC],
+		Segments:                &qrSegments,
+	}, nil
+}
@@ -0,0 +1,74 @@
+package thaiqr_test
+
+import (
+	"github.com/Jdemon/thaiqr"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGenerateLaoQRMustValid(t *testing.T) {
+	qr := thaiqr.NewLaoQR()
+	cmd := thaiqr.LaoQRCmd{
+		MemberBankID: "001",
+		MerchantID:   "LA000123",
+		Amount:       "120000",
+		MerchantName: "KHAO PIAK",
+		TerminalID:   "T1",
+	}
+	actualPayload, err := qr.GeneratePayload(cmd)
+	assert.Nil(t, err)
+	assert.Equal(t, "00020101021238390016A00526628466257701030010208LA00012352040000530341854061200005802LA"+
+		"5909KHAO PIAK6009Vientiane62060702T16304464E", actualPayload)
+
+	result, err := qr.Reader(actualPayload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.GUIDLAPNet, result.GUID)
+	assert.Equal(t, "001", result.MemberBankID)
+	assert.Equal(t, cmd.MerchantID, result.MerchantID)
+	assert.Equal(t, "LAK", result.TransactionCurrencyCode)
+	assert.Equal(t, cmd.Amount, result.TransactionAmount)
+	assert.Equal(t, thaiqr.CountryCodeLA, result.CountryCode)
+	assert.Equal(t, cmd.TerminalID, result.TerminalID)
+
+	parsed, err := thaiqr.Parse(actualPayload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.QRTypeLaoQR, parsed.Type)
+}
+
+func TestLaoQRAndSlipVerificationShareBankIDs(t *testing.T) {
+	payment, err := thaiqr.NewLaoQR().GeneratePayload(thaiqr.LaoQRCmd{MemberBankID: "006", MerchantID: "LA000123"})
+	assert.Nil(t, err)
+	result, err := thaiqr.NewLaoQR().Reader(payment)
+	assert.Nil(t, err)
+
+	payload, err := thaiqr.NewVerifyPaySlipQR().GeneratePayload(thaiqr.VerifyPaySlipQRCmd{
+		TransactionRef: "1234567890123456789012345",
+		SendingBankID:  result.MemberBankID,
+		CountryCode:    thaiqr.CountryCodeLA,
+	})
+	assert.Nil(t, err)
+
+	parsed, err := thaiqr.Parse(payload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.QRTypeVerifyPaySlip, parsed.Type)
+	assert.Equal(t, "006", parsed.VerifyPaySlip.Payload.SendingBankID)
+}
+
+func TestGenerateLaoQRInvalid(t *testing.T) {
+	qr := thaiqr.NewLaoQR()
+	_, err := qr.GeneratePayload(thaiqr.LaoQRCmd{MemberBankID: "BCEL", MerchantID: "LA000123"})
+	assert.EqualError(t, err, "invalid member bank id")
+
+	_, err = qr.GeneratePayload(thaiqr.LaoQRCmd{MemberBankID: "001"})
+	assert.Error(t, err)
+
+	_, err = qr.GeneratePayload(thaiqr.LaoQRCmd{MemberBankID: "001", MerchantID: "LA000123", Amount: "1.5"})
+	assert.Error(t, err)
+}
+
+func TestReadLaoQRShortPayload(t *testing.T) {
+	for _, data := range []string{"", "ab", "abcd", "000201"} {
+		_, err := thaiqr.NewLaoQR().Reader(data)
+		assert.Error(t, err, data)
+	}
+}
@@ -0,0 +1,97 @@
+package thaiqr
+
+import (
+	"bytes"
+	_ "embed"
+	"image"
+	_ "image/png"
+	"io/fs"
+	"reflect"
+	"sync"
+)
+
+//go:embed assets/thaiqr.png
+var thaiQRLogoPNG []byte
+
+var (
+	thaiQRLogoOnce sync.Once
+	thaiQRLogo     image.Image
+	thaiQRLogoErr  error
+
+	// fsLogos caches the last maxFSLogos logos decoded from a comparable fs.FS. fsLogoKeys
+	// holds their keys oldest first, so the oldest is evicted once the cache is full.
+	fsLogosMu  sync.Mutex
+	fsLogos    = make(map[fsLogoKey]image.Image)
+	fsLogoKeys []fsLogoKey
+)
+
+const maxFSLogos = 16
+
+type fsLogoKey struct {
+	fsys fs.FS
+	name string
+}
+
+// ThaiQRLogo returns the bundled Thai QR Payment logo. It is decoded once and shared.
+func ThaiQRLogo() (image.Image, error) {
+	thaiQRLogoOnce.Do(func() {
+		thaiQRLogo, _, thaiQRLogoErr = image.Decode(bytes.NewReader(thaiQRLogoPNG))
+	})
+	return thaiQRLogo, thaiQRLogoErr
+}
+
+// loadLogo resolves the logo selected by the options, falling back to the bundled one.
+func (o *options) loadLogo() (image.Image, error) {
+	if o.logo != nil {
+		return o.logo, nil
+	}
+	if o.logoFS == nil {
+		return ThaiQRLogo()
+	}
+
+	cacheable := reflect.ValueOf(o.logoFS).Comparable()
+	key := fsLogoKey{fsys: o.logoFS, name: o.logoName}
+	if cacheable {
+		if logo, ok := cachedFSLogo(key); ok {
+			return logo, nil
+		}
+	}
+
+	file, err := o.logoFS.Open(o.logoName)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	logo, _, err := image.Decode(file)
+	if err != nil {
+		return nil, err
+	}
+	if cacheable {
+		cacheFSLogo(key, logo)
+	}
+	return logo, nil
+}
+
+func cachedFSLogo(key fsLogoKey) (image.Image, bool) {
+	fsLogosMu.Lock()
+	defer fsLogosMu.Unlock()
+	logo, ok := fsLogos[key]
+	return logo, ok
+}
+
+func cacheFSLogo(key fsLogoKey, logo image.Image) {
+	fsLogosMu.Lock()
+	defer fsLogosMu.Unlock()
+	if _, ok := fsLogos[key]; ok {
+		return
+	}
+	if len(fsLogoKeys) == maxFSLogos {
+		delete(fsLogos, fsLogoKeys[0])
+		fsLogoKeys = fsLogoKeys[1:]
+	}
+	fsLogos[key] = logo
+	fsLogoKeys = append(fsLogoKeys, key)
+}
@@ -0,0 +1,145 @@
+package thaiqr
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	// MMQRIDGUID MMQR Merchant Account Information Tag 26
+	MMQRIDGUID       = "00"
+	MMQRIDAcquirerID = "01"
+	MMQRIDMerchantID = "02"
+
+	MMQRIDTag62BillNumber = "01"
+	MMQRIDTag62TerminalID = "07"
+
+	IDMerchantInformationMMQR = "26"
+	GUIDMMQR                  = "MM.COM.MMQR"
+	TransactionCurrencyMMK    = "104"
+	CountryCodeMM             = "MM"
+)
+
+type MMQRCmd struct {
+	AcquirerID           string `json:"acquirerId"`
+	MerchantID           string `json:"merchantId"`
+	Amount               string `json:"amount"`
+	MerchantCategoryCode string `json:"merchantCategoryCode"`
+	MerchantName         string `json:"merchantName"`
+	MerchantCity         string `json:"merchantCity"`
+	BillNumber           string `json:"billNumber"`
+	TerminalID           string `json:"terminalId"`
+}
+
+type MMQRResults struct {
+	PayloadFormatIndicator  string     `json:"payloadFormatIndicator"`
+	PointOfInitiationMethod string     `json:"pointOfInitiationMethod"`
+	GUID                    string     `json:"guid"`
+	AcquirerID              string     `json:"acquirerId"`
+	MerchantID              string     `json:"merchantId"`
+	MerchantCategoryCode    string     `json:"merchantCategoryCode,omitempty"`
+	TransactionCurrency     string     `json:"transactionCurrency"`
+	TransactionCurrencyCode string     `json:"transactionCurrencyCode"`
+	TransactionAmount       string     `json:"transactionAmount,omitempty"`
+	CountryCode             string     `json:"countryCode"`
+	MerchantName            string     `json:"merchantName,omitempty"`
+	MerchantCity            string     `json:"merchantCity,omitempty"`
+	BillNumber              string     `json:"billNumber,omitempty"`
+	TerminalID              string     `json:"terminalId,omitempty"`
+	CRC                     string     `json:"crc"`
+	Segments                *[]Segment `json:"segments,omitempty"`
+}
+
+// MMQR represents a Myanmar MMQR QR code generator.
+type MMQR struct{}
+
+// NewMMQR returns a new MMQR instance.
+func NewMMQR() *MMQR {
+	return &MMQR{}
+}
+
+// GeneratePayload generates an MMQR payload. MMK is quoted without minor units, so the amount must be a whole number.
+func (qr *MMQR) GeneratePayload(cmd MMQRCmd) (string, error) {
+	if strings.TrimSpace(cmd.AcquirerID) == "" || strings.TrimSpace(cmd.MerchantID) == "" {
+		return "", errors.New("acquirer id and merchant id are required")
+	}
+
+	amount := strings.TrimSpace(cmd.Amount)
+	merchantInfoData := &payloadBuilder{}
+	merchantInfoData.field(MMQRIDGUID, GUIDMMQR)
+	merchantInfoData.field(MMQRIDAcquirerID, cmd.AcquirerID)
+	merchantInfoData.field(MMQRIDMerchantID, cmd.MerchantID)
+
+	data := &payloadBuilder{}
+	data.field(IDPayloadFormat, PayloadFormatEMVQRCPSMerchantPresentedMode)
+	data.field(IDPOIMethod, ifThenElse(amount != "", POIMethodDynamic, POIMethodStatic).(string))
+	data.template(IDMerchantInformationMMQR, merchantInfoData)
+	data.field(IDMerchantCategoryCode, ifThenElse(cmd.MerchantCategoryCode != "", cmd.MerchantCategoryCode, "0000").(string))
+	data.field(IDTransactionCurrency, TransactionCurrencyMMK)
+	if amount != "" {
+		amountFormat, err := formatAmountWithExponent(amount, 0)
+		if err != nil {
+			return "", err
+		}
+		data.field(IDTransactionAmount, amountFormat)
+	}
+	data.field(IDCountryCode, CountryCodeMM)
+	data.field(IDMerchantName, ifThenElse(cmd.MerchantName != "", cmd.MerchantName, "NA").(string))
+	data.field(IDMerchantCity, ifThenElse(cmd.MerchantCity != "", cmd.MerchantCity, "YANGON").(string))
+
+	additionalData := &payloadBuilder{}
+	if strings.TrimSpace(cmd.BillNumber) != "" {
+		additionalData.field(MMQRIDTag62BillNumber, cmd.BillNumber)
+	}
+	if strings.TrimSpace(cmd.TerminalID) != "" {
+		additionalData.field(MMQRIDTag62TerminalID, cmd.TerminalID)
+	}
+	if !additionalData.empty() {
+		data.template(IDAdditionalFields, additionalData)
+	}
+
+	return data.withChecksum(IDCRC)
+}
+
+// Reader decodes an MMQR payload.
+func (qr *MMQR) Reader(data string) (*MMQRResults, error) {
+	qrFields, qrSegments, err := deserializeMerchantPresented(data)
+	if err != nil {
+		return nil, err
+	}
+
+	_, merchantFields, _, ok := findMerchantAccount(qrSegments, GUIDMMQR)
+	if !ok {
+		return nil, errors.New("mmqr merchant account not found")
+	}
+
+	transactionCurrency := qrFields[IDTransactionCurrency]
+	if transactionCurrency != TransactionCurrencyMMK {
+		return nil, errors.New("invalid currency")
+	}
+	countryCode := qrFields[IDCountryCode]
+	if len(countryCode) != 2 {
+		return nil, invalidFormat()
+	}
+
+	additionalFields, _, _ := deserialize(qrFields[IDAdditionalFields])
+
+	return &MMQRResults{
+		PayloadFormatIndicator:  qrFields[IDPayloadFormat],
+		PointOfInitiationMethod: qrFields[IDPOIMethod],
+		GUID:                    merchantFields[MMQRIDGUID],
+		AcquirerID:              merchantFields[MMQRIDAcquirerID],
+		MerchantID:              merchantFields[MMQRIDMerchantID],
+		MerchantCategoryCode:    qrFields[IDMerchantCategoryCode],
+		TransactionCurrency:     transactionCurrency,
+		TransactionCurrencyCode: GetCurrencyCode(transactionCurrency),
+		TransactionAmount:       qrFields[IDTransactionAmount],
+		CountryCode:             countryCode,
+		MerchantName:            qrFields[IDMerchantName],
+		MerchantCity:            qrFields[IDMerchantCity],
+		BillNumber:              additionalFields[MMQRIDTag62BillNumber],
+		TerminalID:              additionalFields[MMQRIDTag62TerminalID],
+		CRC:                     qrFields[IDCRC],
+		Segments:                &qrSegments,
+	}, nil
+}
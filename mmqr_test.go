@@ -0,0 +1,52 @@
+package thaiqr_test
+
+import (
+	"github.com/Jdemon/thaiqr"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGenerateMMQRMustValid(t *testing.T) {
+	qr := thaiqr.NewMMQR()
+	cmd := thaiqr.MMQRCmd{
+		AcquirerID:   "KBZ",
+		MerchantID:   "M0001",
+		Amount:       "5000",
+		MerchantName: "TEA SHOP",
+		BillNumber:   "B1",
+	}
+	actualPayload, err := qr.GeneratePayload(cmd)
+	assert.Nil(t, err)
+	assert.Equal(t, "00020101021226310011MM.COM.MMQR0103KBZ0205M0001520400005303104540450005802MM"+
+		"5908TEA SHOP6006YANGON62060102B163048573", actualPayload)
+
+	result, err := qr.Reader(actualPayload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.GUIDMMQR, result.GUID)
+	assert.Equal(t, cmd.AcquirerID, result.AcquirerID)
+	assert.Equal(t, cmd.MerchantID, result.MerchantID)
+	assert.Equal(t, "MMK", result.TransactionCurrencyCode)
+	assert.Equal(t, cmd.Amount, result.TransactionAmount)
+	assert.Equal(t, thaiqr.CountryCodeMM, result.CountryCode)
+	assert.Equal(t, cmd.BillNumber, result.BillNumber)
+
+	parsed, err := thaiqr.Parse(actualPayload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.QRTypeMMQR, parsed.Type)
+}
+
+func TestGenerateMMQRInvalid(t *testing.T) {
+	qr := thaiqr.NewMMQR()
+	_, err := qr.GeneratePayload(thaiqr.MMQRCmd{MerchantID: "M0001"})
+	assert.Error(t, err)
+
+	_, err = qr.GeneratePayload(thaiqr.MMQRCmd{AcquirerID: "KBZ", MerchantID: "M0001", Amount: "10.5"})
+	assert.Error(t, err)
+}
+
+func TestReadMMQRShortPayload(t *testing.T) {
+	for _, data := range []string{"", "ab", "abcd", "000201"} {
+		_, err := thaiqr.NewMMQR().Reader(data)
+		assert.Error(t, err, data)
+	}
+}
@@ -0,0 +1,53 @@
+package thaiqr
+
+import (
+	"image"
+	"io/fs"
+)
+
+// Option customises how QR images are generated.
+type Option func(*options)
+
+type options struct {
+	logo     image.Image
+	logoFS   fs.FS
+	logoName string
+	render   RenderOptions
+	verify   bool
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithLogo overlays the given image instead of the bundled Thai QR logo.
+func WithLogo(logo image.Image) Option {
+	return func(o *options) {
+		o.logo = logo
+		o.logoFS = nil
+		o.logoName = ""
+	}
+}
+
+// WithLogoFS overlays the image stored at name in fsys instead of the bundled Thai QR logo.
+// When fsys is comparable, e.g. an embed.FS or os.DirFS, the decoded image is cached; only the
+// most recently decoded logos are kept.
+func WithLogoFS(fsys fs.FS, name string) Option {
+	return func(o *options) {
+		o.logo = nil
+		o.logoFS = fsys
+		o.logoName = name
+	}
+}
+
+// WithVerify decodes every rendered QR image and fails with ErrQRNotDecodable when it does not read back
+// as its payload, so a logo or colour choice can never ship an unscannable code.
+func WithVerify() Option {
+	return func(o *options) {
+		o.verify = true
+	}
+}
@@ -0,0 +1,202 @@
+package thaiqr
+
+import (
+	"strings"
+)
+
+// QRType identifies the kind of payload detected by Parse.
+type QRType string
+
+const (
+	QRTypeUnknown       QRType = "UNKNOWN"
+	QRTypePromptPay     QRType = "PROMPTPAY"
+	QRTypeVerifyPaySlip QRType = "VERIFY_PAY_SLIP"
+	QRTypeEMV           QRType = "EMV"
+	QRTypePayNow        QRType = "PAYNOW"
+	QRTypeDuitNow       QRType = "DUITNOW"
+	QRTypeQRIS          QRType = "QRIS"
+	QRTypeVietQR        QRType = "VIETQR"
+	QRTypeKHQR          QRType = "KHQR"
+	QRTypeLaoQR         QRType = "LAOQR"
+	QRTypeQRPh          QRType = "QRPH"
+	QRTypeMMQR          QRType = "MMQR"
+	QRTypeScheme        QRType = "SCHEME"
+)
+
+// GUIDPromptPayPrefix is the registered application provider ID shared by all PromptPay AIDs.
+const GUIDPromptPayPrefix = "A000000677"
+
+// Parsed holds the result of Parse. Only the field matching Type is populated, except for
+// QRTypeScheme where Scheme and SchemeTemplate come with the generic EMV decoding.
+type Parsed struct {
+	Type           QRType                 `json:"type"`
+	PromptPay      *PromptPayQRResults    `json:"promptPay,omitempty"`
+	VerifyPaySlip  *VerifyPaySlipQRResult `json:"verifyPaySlip,omitempty"`
+	EMV            *PromptPayQRResults    `json:"emv,omitempty"`
+	PayNow         *PayNowQRResults       `json:"payNow,omitempty"`
+	DuitNow        *DuitNowQRResults      `json:"duitNow,omitempty"`
+	QRIS           *QRISQRResults         `json:"qris,omitempty"`
+	VietQR         *VietQRResults         `json:"vietQr,omitempty"`
+	KHQR           *KHQRResults           `json:"khqr,omitempty"`
+	LaoQR          *LaoQRResults          `json:"laoQr,omitempty"`
+	QRPh           *QRPhResults           `json:"qrPh,omitempty"`
+	MMQR           *MMQRResults           `json:"mmqr,omitempty"`
+	Scheme         string                 `json:"scheme,omitempty"`
+	SchemeTemplate any                    `json:"schemeTemplate,omitempty"`
+}
+
+// Parse detects the format of a QR payload and decodes it with the matching reader.
+//
+// EMV merchant presented payloads are first matched against the registered schemes, built-in
+// schemes first, and must pass the scheme's Validate. Payloads of the built-in PromptPay schemes
+// are returned as QRTypePromptPay and those of a scheme added with RegisterScheme as QRTypeScheme.
+// Other payloads of a supported network are returned with their own type, and any other EMV
+// merchant presented payload, including one carrying a PromptPay template that fails validation,
+// as QRTypeEMV.
+func Parse(data string) (Parsed, error) {
+	if fields, segments, err := deserialize(data); err == nil && fields[IDPayloadFormat] == PayloadFormatEMVQRCPSMerchantPresentedMode {
+		if scheme, ok := detectScheme(fields, segments); ok {
+			return parseScheme(data, fields, scheme)
+		}
+	}
+
+	switch DetectQRType(data) {
+	case QRTypePromptPay, QRTypeEMV:
+		// valid PromptPay payloads were claimed by their scheme above
+		result, err := NewPromptPayQR().Reader(data)
+		if err != nil {
+			return Parsed{}, err
+		}
+		return Parsed{Type: QRTypeEMV, EMV: result}, nil
+	case QRTypePayNow:
+		result, err := NewPayNowQR().Reader(data)
+		if err != nil {
+			return Parsed{}, err
+		}
+		return Parsed{Type: QRTypePayNow, PayNow: result}, nil
+	case QRTypeDuitNow:
+		result, err := NewDuitNowQR().Reader(data)
+		if err != nil {
+			return Parsed{}, err
+		}
+		return Parsed{Type: QRTypeDuitNow, DuitNow: result}, nil
+	case QRTypeQRIS:
+		result, err := NewQRISQR().Reader(data)
+		if err != nil {
+			return Parsed{}, err
+		}
+		return Parsed{Type: QRTypeQRIS, QRIS: result}, nil
+	case QRTypeVietQR:
+		result, err := NewVietQR().Reader(data)
+		if err != nil {
+			return Parsed{}, err
+		}
+		return Parsed{Type: QRTypeVietQR, VietQR: result}, nil
+	case QRTypeKHQR:
+		result, err := NewKHQR().Reader(data)
+		if err != nil {
+			return Parsed{}, err
+		}
+		return Parsed{Type: QRTypeKHQR, KHQR: result}, nil
+	case QRTypeLaoQR:
+		result, err := NewLaoQR().Reader(data)
+		if err != nil {
+			return Parsed{}, err
+		}
+		return Parsed{Type: QRTypeLaoQR, LaoQR: result}, nil
+	case QRTypeQRPh:
+		result, err := NewQRPh().Reader(data)
+		if err != nil {
+			return Parsed{}, err
+		}
+		return Parsed{Type: QRTypeQRPh, QRPh: result}, nil
+	case QRTypeMMQR:
+		result, err := NewMMQR().Reader(data)
+		if err != nil {
+			return Parsed{}, err
+		}
+		return Parsed{Type: QRTypeMMQR, MMQR: result}, nil
+	case QRTypeVerifyPaySlip:
+		result, err := NewVerifyPaySlipQR().Reader(data)
+		if err != nil {
+			return Parsed{}, err
+		}
+		return Parsed{Type: QRTypeVerifyPaySlip, VerifyPaySlip: result}, nil
+	default:
+		return Parsed{Type: QRTypeUnknown}, unsupportedFormat()
+	}
+}
+
+// parseScheme decodes a merchant presented payload recognised and validated by scheme.
+func parseScheme(data string, fields map[string]string, scheme Scheme) (Parsed, error) {
+	result, err := NewPromptPayQR().Reader(data)
+	if err != nil {
+		return Parsed{}, err
+	}
+	if scheme == PromptPayCreditTransferScheme || scheme == PromptPayBillPaymentScheme {
+		return Parsed{Type: QRTypePromptPay, PromptPay: result}, nil
+	}
+
+	template, err := scheme.DecodeTemplate(fields)
+	if err != nil {
+		return Parsed{}, err
+	}
+	return Parsed{Type: QRTypeScheme, EMV: result, Scheme: scheme.Name(), SchemeTemplate: template}, nil
+}
+
+// DetectQRType inspects the top level structure of a payload without validating it.
+func DetectQRType(data string) QRType {
+	fields, segments, err := deserialize(data)
+	if err != nil {
+		return QRTypeUnknown
+	}
+
+	if fields[IDPayloadFormat] == PayloadFormatEMVQRCPSMerchantPresentedMode {
+		if PromptPayCreditTransferScheme.Detect(fields, segments) || PromptPayBillPaymentScheme.Detect(fields, segments) {
+			return QRTypePromptPay
+		}
+		if _, _, _, ok := findMerchantAccount(segments, GUIDPayNow); ok {
+			return QRTypePayNow
+		}
+		if _, _, _, ok := findMerchantAccount(segments, GUIDDuitNow); ok {
+			return QRTypeDuitNow
+		}
+		if _, _, _, ok := findMerchantAccount(segments, GUIDQRIS); ok {
+			return QRTypeQRIS
+		}
+		if _, _, _, ok := findMerchantAccount(segments, GUIDNAPAS); ok {
+			return QRTypeVietQR
+		}
+		if _, _, _, ok := findMerchantAccount(segments, GUIDLAPNet); ok {
+			return QRTypeLaoQR
+		}
+		for _, guid := range []string{GUIDQRPhP2P, GUIDQRPhP2M} {
+			if _, _, _, ok := findMerchantAccount(segments, guid); ok {
+				return QRTypeQRPh
+			}
+		}
+		if _, _, _, ok := findMerchantAccount(segments, GUIDMMQR); ok {
+			return QRTypeMMQR
+		}
+		if isKHQR(fields) {
+			return QRTypeKHQR
+		}
+		return QRTypeEMV
+	}
+
+	payloadFields, _, err := deserialize(fields[IDQrVerifyPayload])
+	if err == nil && payloadFields[IDPayloadAPIID] == VerifyPaySlipAPIID {
+		return QRTypeVerifyPaySlip
+	}
+
+	return QRTypeUnknown
+}
+
+// isPromptPayTemplate reports whether a merchant account template carries a PromptPay AID.
+func isPromptPayTemplate(data string) bool {
+	fields, _, err := deserialize(data)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(fields[BOTIDCreditTransferAID], GUIDPromptPayPrefix)
+}
@@ -0,0 +1,76 @@
+package thaiqr_test
+
+import (
+	"github.com/Jdemon/thaiqr"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParsePromptPayCreditTransfer(t *testing.T) {
+	payload := "00020101021229370016A0000006770101110113006690976485653037645802TH540510.006304CF65"
+	assert.Equal(t, thaiqr.QRTypePromptPay, thaiqr.DetectQRType(payload))
+
+	parsed, err := thaiqr.Parse(payload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.QRTypePromptPay, parsed.Type)
+	assert.NotNil(t, parsed.PromptPay)
+	assert.Nil(t, parsed.VerifyPaySlip)
+	assert.Nil(t, parsed.EMV)
+	assert.Equal(t, "0066909764856", parsed.PromptPay.CreditTransfer.MSISDN)
+}
+
+func TestParsePromptPayBillPayment(t *testing.T) {
+	payload := "00020101021230570016A00000067701011201153110400394751010206REF0010304REF253037645406555.555802TH62100706SCB001630437C6"
+
+	parsed, err := thaiqr.Parse(payload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.QRTypePromptPay, parsed.Type)
+	assert.Equal(t, "311040039475101", parsed.PromptPay.BillPayment.BillerID)
+}
+
+func TestParseVerifyPaySlip(t *testing.T) {
+	payload := "003700060000010103006021620231130773524225102TH9104EC49"
+	assert.Equal(t, thaiqr.QRTypeVerifyPaySlip, thaiqr.DetectQRType(payload))
+
+	parsed, err := thaiqr.Parse(payload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.QRTypeVerifyPaySlip, parsed.Type)
+	assert.Nil(t, parsed.PromptPay)
+	assert.Equal(t, "2023113077352422", parsed.VerifyPaySlip.Payload.TransactionRef)
+}
+
+func TestParseForeignEMV(t *testing.T) {
+	payload := "00020101021126390012HK.COM.HKICL011912345678901234567895204000053033445802HK5902AB6009Hong Kong63046103"
+	assert.Equal(t, thaiqr.QRTypeEMV, thaiqr.DetectQRType(payload))
+
+	parsed, err := thaiqr.Parse(payload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.QRTypeEMV, parsed.Type)
+	assert.Nil(t, parsed.PromptPay)
+	assert.Equal(t, "HK", parsed.EMV.CountryCode)
+	assert.Equal(t, "344", parsed.EMV.TransactionCurrency)
+}
+
+func TestParsePayNow(t *testing.T) {
+	payload := "00020101021126330009SG.PAYNOW010100211+658123456753037025802SG5902AB6009Singapore630495CE"
+	assert.Equal(t, thaiqr.QRTypePayNow, thaiqr.DetectQRType(payload))
+
+	parsed, err := thaiqr.Parse(payload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.QRTypePayNow, parsed.Type)
+	assert.Nil(t, parsed.EMV)
+	assert.Equal(t, "+6581234567", parsed.PayNow.ProxyValue)
+}
+
+func TestParseUnknown(t *testing.T) {
+	assert.Equal(t, thaiqr.QRTypeUnknown, thaiqr.DetectQRType("hello world"))
+
+	parsed, err := thaiqr.Parse("hello world")
+	assert.Error(t, err)
+	assert.Equal(t, thaiqr.QRTypeUnknown, parsed.Type)
+}
+
+func TestParseInvalidChecksum(t *testing.T) {
+	_, err := thaiqr.Parse("00020101021229370016A0000006770101110113006690976485653037645802TH540510.006304CF66")
+	assert.Error(t, err)
+}
@@ -0,0 +1,186 @@
+package thaiqr
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	// PayNowIDGUID PayNow Merchant Account Information Tag 26
+	PayNowIDGUID       = "00"
+	PayNowIDProxyType  = "01"
+	PayNowIDProxyValue = "02"
+	PayNowIDEditable   = "03"
+	PayNowIDExpiryDate = "04"
+
+	PayNowIDTag62BillNumber = "01"
+
+	IDMerchantInformationPayNow = "26"
+	TransactionCurrencySGD      = "702"
+	CountryCodeSG               = "SG"
+
+	PayNowProxyTypeMobile = "MOBILE"
+	PayNowProxyTypeUEN    = "UEN"
+
+	payNowProxyTypeMobileCode = "0"
+	payNowProxyTypeUENCode    = "2"
+	payNowExpiryDateLayout    = "20060102"
+)
+
+type PayNowQRCmd struct {
+	ProxyType    string `json:"proxyType"`
+	ProxyValue   string `json:"proxyValue"`
+	Amount       string `json:"amount"`
+	Editable     bool   `json:"editable"`
+	ExpiryDate   string `json:"expiryDate"`
+	MerchantName string `json:"merchantName"`
+	MerchantCity string `json:"merchantCity"`
+	BillNumber   string `json:"billNumber"`
+}
+
+type PayNowQRResults struct {
+	PayloadFormatIndicator  string     `json:"payloadFormatIndicator"`
+	PointOfInitiationMethod string     `json:"pointOfInitiationMethod"`
+	ProxyType               string     `json:"proxyType"`
+	ProxyValue              string     `json:"proxyValue"`
+	Editable                bool       `json:"editable"`
+	ExpiryDate              string     `json:"expiryDate,omitempty"`
+	MerchantCategoryCode    string     `json:"merchantCategoryCode,omitempty"`
+	TransactionCurrency     string     `json:"transactionCurrency"`
+	TransactionCurrencyCode string     `json:"transactionCurrencyCode"`
+	TransactionAmount       string     `json:"transactionAmount,omitempty"`
+	CountryCode             string     `json:"countryCode"`
+	MerchantName            string     `json:"merchantName,omitempty"`
+	MerchantCity            string     `json:"merchantCity,omitempty"`
+	BillNumber              string     `json:"billNumber,omitempty"`
+	CRC                     string     `json:"crc"`
+	Segments                *[]Segment `json:"segments,omitempty"`
+}
+
+// PayNowQR represents a Singapore SGQR/PayNow QR code generator.
+type PayNowQR struct{}
+
+// NewPayNowQR returns a new PayNowQR instance.
+func NewPayNowQR() *PayNowQR {
+	return &PayNowQR{}
+}
+
+// GeneratePayload generates a PayNow QR code payload.
+func (qr *PayNowQR) GeneratePayload(cmd PayNowQRCmd) (string, error) {
+	proxyTypeCode, err := payNowProxyTypeCode(cmd.ProxyType)
+	if err != nil {
+		return "", err
+	}
+	proxyValue := strings.TrimSpace(cmd.ProxyValue)
+	if proxyValue == "" {
+		return "", errors.New("proxy value is required")
+	}
+
+	merchantInfoData := &payloadBuilder{}
+	merchantInfoData.field(PayNowIDGUID, GUIDPayNow)
+	merchantInfoData.field(PayNowIDProxyType, proxyTypeCode)
+	merchantInfoData.field(PayNowIDProxyValue, proxyValue)
+	merchantInfoData.field(PayNowIDEditable, ifThenElse(cmd.Editable, "1", "0").(string))
+	if cmd.ExpiryDate != "" {
+		if _, err := time.Parse(payNowExpiryDateLayout, cmd.ExpiryDate); err != nil {
+			return "", errors.New("invalid expiry date")
+		}
+		merchantInfoData.field(PayNowIDExpiryDate, cmd.ExpiryDate)
+	}
+
+	amount := strings.TrimSpace(cmd.Amount)
+	data := &payloadBuilder{}
+	data.field(IDPayloadFormat, PayloadFormatEMVQRCPSMerchantPresentedMode)
+	data.field(IDPOIMethod, ifThenElse(amount != "", POIMethodDynamic, POIMethodStatic).(string))
+	data.template(IDMerchantInformationPayNow, merchantInfoData)
+	data.field(IDMerchantCategoryCode, "0000")
+	data.field(IDTransactionCurrency, TransactionCurrencySGD)
+	if amount != "" {
+		amountFormat, err := formatAmountWithExponent(amount, 2)
+		if err != nil {
+			return "", err
+		}
+		data.field(IDTransactionAmount, amountFormat)
+	}
+	data.field(IDCountryCode, CountryCodeSG)
+	data.field(IDMerchantName, ifThenElse(cmd.MerchantName != "", cmd.MerchantName, "NA").(string))
+	data.field(IDMerchantCity, ifThenElse(cmd.MerchantCity != "", cmd.MerchantCity, "Singapore").(string))
+	if strings.TrimSpace(cmd.BillNumber) != "" {
+		additionalData := &payloadBuilder{}
+		additionalData.field(PayNowIDTag62BillNumber, cmd.BillNumber)
+		data.template(IDAdditionalFields, additionalData)
+	}
+
+	return data.withChecksum(IDCRC)
+}
+
+// Reader decodes a PayNow QR code payload.
+func (qr *PayNowQR) Reader(data string) (*PayNowQRResults, error) {
+	qrFields, qrSegments, err := deserializeMerchantPresented(data)
+	if err != nil {
+		return nil, err
+	}
+
+	_, merchantFields, _, ok := findMerchantAccount(qrSegments, GUIDPayNow)
+	if !ok {
+		return nil, errors.New("paynow merchant account not found")
+	}
+
+	proxyType, err := payNowProxyType(merchantFields[PayNowIDProxyType])
+	if err != nil {
+		return nil, err
+	}
+
+	transactionCurrency := qrFields[IDTransactionCurrency]
+	if transactionCurrency != TransactionCurrencySGD {
+		return nil, errors.New("invalid currency")
+	}
+	countryCode := qrFields[IDCountryCode]
+	if len(countryCode) != 2 {
+		return nil, invalidFormat()
+	}
+
+	additionalFields, _, _ := deserialize(qrFields[IDAdditionalFields])
+
+	return &PayNowQRResults{
+		PayloadFormatIndicator:  qrFields[IDPayloadFormat],
+		PointOfInitiationMethod: qrFields[IDPOIMethod],
+		ProxyType:               proxyType,
+		ProxyValue:              merchantFields[PayNowIDProxyValue],
+		Editable:                merchantFields[PayNowIDEditable] == "1",
+		ExpiryDate:              merchantFields[PayNowIDExpiryDate],
+		MerchantCategoryCode:    qrFields[IDMerchantCategoryCode],
+		TransactionCurrency:     transactionCurrency,
+		TransactionCurrencyCode: GetCurrencyCode(transactionCurrency),
+		TransactionAmount:       qrFields[IDTransactionAmount],
+		CountryCode:             countryCode,
+		MerchantName:            qrFields[IDMerchantName],
+		MerchantCity:            qrFields[IDMerchantCity],
+		BillNumber:              additionalFields[PayNowIDTag62BillNumber],
+		CRC:                     qrFields[IDCRC],
+		Segments:                &qrSegments,
+	}, nil
+}
+
+func payNowProxyTypeCode(proxyType string) (string, error) {
+	switch strings.ToUpper(proxyType) {
+	case PayNowProxyTypeMobile:
+		return payNowProxyTypeMobileCode, nil
+	case PayNowProxyTypeUEN:
+		return payNowProxyTypeUENCode, nil
+	default:
+		return "", errors.New("invalid proxy type")
+	}
+}
+
+func payNowProxyType(code string) (string, error) {
+	switch code {
+	case payNowProxyTypeMobileCode:
+		return PayNowProxyTypeMobile, nil
+	case payNowProxyTypeUENCode:
+		return PayNowProxyTypeUEN, nil
+	default:
+		return "", errors.New("invalid proxy type")
+	}
+}
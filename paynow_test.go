@@ -0,0 +1,92 @@
+package thaiqr_test
+
+import (
+	"github.com/Jdemon/thaiqr"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGeneratePayNowUEN(t *testing.T) {
+	qr := thaiqr.NewPayNowQR()
+	cmd := thaiqr.PayNowQRCmd{
+		ProxyType:    thaiqr.PayNowProxyTypeUEN,
+		ProxyValue:   "201403121W",
+		Amount:       "12.30",
+		ExpiryDate:   "20261231",
+		MerchantName: "LIMITED CO",
+		BillNumber:   "INV001",
+	}
+	actualPayload, err := qr.GeneratePayload(cmd)
+	assert.Nil(t, err)
+	assert.Equal(t, "00020101021226490009SG.PAYNOW010120210201403121W03010040820261231520400005303702540512.30"+
+		"5802SG5910LIMITED CO6009Singapore62100106INV0016304B299", actualPayload)
+
+	result, err := qr.Reader(actualPayload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.PayNowProxyTypeUEN, result.ProxyType)
+	assert.Equal(t, cmd.ProxyValue, result.ProxyValue)
+	assert.False(t, result.Editable)
+	assert.Equal(t, cmd.ExpiryDate, result.ExpiryDate)
+	assert.Equal(t, cmd.Amount, result.TransactionAmount)
+	assert.Equal(t, "SGD", result.TransactionCurrencyCode)
+	assert.Equal(t, thaiqr.CountryCodeSG, result.CountryCode)
+	assert.Equal(t, cmd.MerchantName, result.MerchantName)
+	assert.Equal(t, "Singapore", result.MerchantCity)
+	assert.Equal(t, cmd.BillNumber, result.BillNumber)
+	assert.Equal(t, actualPayload[len(actualPayload)-4:], result.CRC)
+}
+
+func TestGeneratePayNowMobileEditable(t *testing.T) {
+	qr := thaiqr.NewPayNowQR()
+	actualPayload, err := qr.GeneratePayload(thaiqr.PayNowQRCmd{
+		ProxyType:  thaiqr.PayNowProxyTypeMobile,
+		ProxyValue: "+6581234567",
+		Editable:   true,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "00020101021126380009SG.PAYNOW010100211+65812345670301152040000530370258"+
+		"02SG5902NA6009Singapore63042A3E", actualPayload)
+
+	result, err := qr.Reader(actualPayload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.PayNowProxyTypeMobile, result.ProxyType)
+	assert.True(t, result.Editable)
+	assert.Equal(t, thaiqr.POIMethodStatic, result.PointOfInitiationMethod)
+}
+
+func TestGeneratePayNowInvalid(t *testing.T) {
+	qr := thaiqr.NewPayNowQR()
+	_, err := qr.GeneratePayload(thaiqr.PayNowQRCmd{ProxyType: "EMAIL", ProxyValue: "a@b.c"})
+	assert.Error(t, err)
+
+	_, err = qr.GeneratePayload(thaiqr.PayNowQRCmd{ProxyType: thaiqr.PayNowProxyTypeMobile, ProxyValue: "+6581234567", ExpiryDate: "2026-12-31"})
+	assert.Error(t, err)
+
+	_, err = qr.GeneratePayload(thaiqr.PayNowQRCmd{ProxyType: thaiqr.PayNowProxyTypeMobile, ProxyValue: "+6581234567", Amount: "1xx"})
+	assert.Error(t, err)
+
+	_, err = qr.GeneratePayload(thaiqr.PayNowQRCmd{ProxyType: thaiqr.PayNowProxyTypeMobile, ProxyValue: "+6581234567", Amount: "-5"})
+	assert.EqualError(t, err, "invalid amount")
+}
+
+func TestGeneratePayNowLargeAmount(t *testing.T) {
+	qr := thaiqr.NewPayNowQR()
+	payload, err := qr.GeneratePayload(thaiqr.PayNowQRCmd{ProxyType: thaiqr.PayNowProxyTypeMobile, ProxyValue: "+6581234567", Amount: "1234567.89"})
+	assert.Nil(t, err)
+
+	result, err := qr.Reader(payload)
+	assert.Nil(t, err)
+	assert.Equal(t, "1234567.89", result.TransactionAmount)
+}
+
+func TestPayNowReaderRejectsPromptPay(t *testing.T) {
+	_, err := thaiqr.NewPayNowQR().Reader("00020101021229370016A0000006770101110113006690976485653037645802TH540510.006304CF65")
+	assert.Error(t, err)
+}
+
+func TestReadPayNowShortPayload(t *testing.T) {
+	for _, data := range []string{"", "ab", "abcd", "000201"} {
+		_, err := thaiqr.NewPayNowQR().Reader(data)
+		assert.Error(t, err, data)
+	}
+}
@@ -0,0 +1,296 @@
+package thaiqr
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+)
+
+// PageSize is a page size in PostScript points (1/72 inch).
+type PageSize struct {
+	Name   string
+	Width  float64
+	Height float64
+}
+
+var (
+	PageSizeA4 = PageSize{Name: "A4", Width: 595.28, Height: 841.89}
+	PageSizeA5 = PageSize{Name: "A5", Width: 419.53, Height: 595.28}
+)
+
+// PDFLayout places QR codes on a grid of Columns x Rows cells per page.
+type PDFLayout struct {
+	PageSize PageSize
+	Columns  int
+	Rows     int
+	// Margin is the blank border around the grid, in points.
+	Margin float64
+}
+
+var (
+	// PDFLayoutA4 prints one large QR per A4 page, e.g. for a counter display.
+	PDFLayoutA4 = PDFLayout{PageSize: PageSizeA4, Columns: 1, Rows: 1, Margin: 56.69}
+	// PDFLayoutA5 prints one QR per A5 page, e.g. for a table tent or an invoice insert.
+	PDFLayoutA5 = PDFLayout{PageSize: PageSizeA5, Columns: 1, Rows: 1, Margin: 42.52}
+	// PDFLayoutLabelSheet prints 24 QR labels per A4 page in a 3 x 8 grid.
+	PDFLayoutLabelSheet = PDFLayout{PageSize: PageSizeA4, Columns: 3, Rows: 8, Margin: 28.35}
+)
+
+// PDFItem is one QR code on a sheet with the caption lines printed under it. Captions are set in
+// Noto Sans Thai, which covers Thai and Latin script; the glyphs used are embedded in the PDF.
+type PDFItem struct {
+	Payload  string
+	Captions []string
+}
+
+// PromptPayPDFItem builds a sheet item for a PromptPay QR, captioned with the merchant name and amount.
+func PromptPayPDFItem(merchantName string, cmd PromptPayQRCmd) (PDFItem, error) {
+	payload, err := NewPromptPayQR().GeneratePayload(cmd)
+	if err != nil {
+		return PDFItem{}, err
+	}
+	captions := captionLines(merchantName)
+	if caption, err := amountCaption(cmd.Amount, cmd.CurrencyCode); err != nil {
+		return PDFItem{}, err
+	} else if caption != "" {
+		captions = append(captions, caption)
+	}
+	return PDFItem{Payload: payload, Captions: captions}, nil
+}
+
+// BillPaymentPDFItem builds a sheet item for a PromptPay bill payment QR, captioned with the merchant
+// name, amount, biller ID and references, e.g. to attach to an invoice.
+func BillPaymentPDFItem(merchantName string, cmd PromptPayBillPaymentQRCmd) (PDFItem, error) {
+	payload, err := NewPromptPayQR().GenerateBillPaymentPayload(cmd)
+	if err != nil {
+		return PDFItem{}, err
+	}
+	captions := captionLines(merchantName)
+	if caption, err := amountCaption(cmd.Amount, cmd.CurrencyCode); err != nil {
+		return PDFItem{}, err
+	} else if caption != "" {
+		captions = append(captions, caption)
+	}
+	captions = append(captions, "Biller ID: "+sanitizeTarget(cmd.BillerID))
+	if cmd.Ref1 != "" {
+		captions = append(captions, "Ref1: "+cmd.Ref1)
+	}
+	if cmd.Ref2 != "" {
+		captions = append(captions, "Ref2: "+cmd.Ref2)
+	}
+	return PDFItem{Payload: payload, Captions: captions}, nil
+}
+
+func captionLines(merchantName string) []string {
+	if strings.TrimSpace(merchantName) == "" {
+		return []string{}
+	}
+	return []string{merchantName}
+}
+
+func amountCaption(amount, currency string) (string, error) {
+	if strings.TrimSpace(amount) == "" {
+		return "", nil
+	}
+	formatted, err := formatAmount(strings.TrimSpace(amount))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Amount: %s %s", formatted, ifThenElse(currency != "", currency, "THB").(string)), nil
+}
+
+// GeneratePDF generates a PDF with the items laid out on as many pages as needed.
+func GeneratePDF(items []PDFItem, layout PDFLayout, opts ...Option) (*[]byte, error) {
+	return generatePDF(items, layout, newOptions(opts), false)
+}
+
+// GeneratePDFWithThaiQRLogo generates a PDF like GeneratePDF, with the Thai QR Payment logo, or the
+// logo given by WithLogo or WithLogoFS, in the center of every QR.
+func GeneratePDFWithThaiQRLogo(items []PDFItem, layout PDFLayout, opts ...Option) (*[]byte, error) {
+	return generatePDF(items, layout, newOptions(opts), true)
+}
+
+func generatePDF(items []PDFItem, layout PDFLayout, o *options, withLogo bool) (*[]byte, error) {
+	if len(items) == 0 {
+		return nil, errors.New("no items")
+	}
+	if layout.Columns <= 0 || layout.Rows <= 0 || layout.PageSize.Width <= 0 || layout.PageSize.Height <= 0 {
+		return nil, errors.New("invalid layout")
+	}
+
+	ro := o.render.normalize()
+	var logo image.Image
+	if withLogo {
+		var err error
+		if logo, err = o.loadLogo(); err != nil {
+			return nil, err
+		}
+	}
+
+	images := make([][]byte, 0, len(items))
+	sizes := make([]int, 0, len(items))
+	for _, item := range items {
+		rendered, err := renderQR(item.Payload, ro, logo, o.verify)
+		if err != nil {
+			return nil, err
+		}
+		data, err := pdfImageData(rendered)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, data)
+		sizes = append(sizes, rendered.Bounds().Dx())
+	}
+
+	captionFont, err := newPDFFont()
+	if err != nil {
+		return nil, err
+	}
+
+	perPage := layout.Columns * layout.Rows
+	pages := (len(items) + perPage - 1) / perPage
+	w := &pdfWriter{}
+	w.header()
+
+	// Objects: 1 catalog, 2 page tree, 3 font, then a page and its content stream per page, then one image
+	// per item, then the four objects describing the embedded font.
+	pageObj := func(page int) int { return 4 + 2*page }
+	imageObj := func(item int) int { return 4 + 2*pages + item }
+	fontObj := imageObj(len(items))
+
+	w.object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	kids := make([]string, pages)
+	for page := range kids {
+		kids[page] = fmt.Sprintf("%d 0 R", pageObj(page))
+	}
+	w.object(2, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), pages))
+
+	cellWidth := (layout.PageSize.Width - 2*layout.Margin) / float64(layout.Columns)
+	cellHeight := (layout.PageSize.Height - 2*layout.Margin) / float64(layout.Rows)
+	fontSize := min(max(cellWidth/30, 6), 16)
+	lineHeight := fontSize * 1.3
+	padding := fontSize / 2
+
+	for page := 0; page < pages; page++ {
+		var content strings.Builder
+		xObjects := make([]string, 0, perPage)
+		for slot := 0; slot < perPage; slot++ {
+			item := page*perPage + slot
+			if item >= len(items) {
+				break
+			}
+			column, row := slot%layout.Columns, slot/layout.Columns
+			cellX := layout.Margin + float64(column)*cellWidth
+			cellTop := layout.PageSize.Height - layout.Margin - float64(row)*cellHeight
+
+			captions := items[item].Captions
+			side := min(cellWidth, cellHeight-float64(len(captions))*lineHeight) - 2*padding
+			if side <= 0 {
+				return nil, errors.New("cell too small for captions")
+			}
+			qrX := cellX + (cellWidth-side)/2
+			qrY := cellTop - padding - side
+			_, _ = fmt.Fprintf(&content, "q %s 0 0 %s %s %s cm /Im%d Do Q\n",
+				pdfNumber(side), pdfNumber(side), pdfNumber(qrX), pdfNumber(qrY), item)
+			xObjects = append(xObjects, fmt.Sprintf("/Im%d %d 0 R", item, imageObj(item)))
+
+			for i, caption := range captions {
+				text := captionFont.truncate(caption, fontSize, cellWidth-2*padding)
+				textX := cellX + (cellWidth-captionFont.width(text, fontSize))/2
+				textY := qrY - float64(i+1)*lineHeight + (lineHeight-fontSize)/2
+				_, _ = fmt.Fprintf(&content, "BT /F1 %s Tf %s %s Td %s ET\n",
+					pdfNumber(fontSize), pdfNumber(textX), pdfNumber(textY), captionFont.show(text, fontSize))
+			}
+		}
+
+		w.object(pageObj(page), fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R >> /XObject << %s >> >> /Contents %d 0 R >>",
+			pdfNumber(layout.PageSize.Width), pdfNumber(layout.PageSize.Height), strings.Join(xObjects, " "), pageObj(page)+1))
+		w.stream(pageObj(page)+1, "", []byte(content.String()))
+	}
+
+	for item, data := range images {
+		w.stream(imageObj(item), fmt.Sprintf(
+			"/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Interpolate false /Filter /FlateDecode ",
+			sizes[item], sizes[item]), data)
+	}
+
+	if err := captionFont.write(w, 3, fontObj); err != nil {
+		return nil, err
+	}
+
+	pdf := w.finish(fontObj + 4)
+	return &pdf, nil
+}
+
+// pdfImageData flattens img onto white and returns its RGB samples, Flate compressed.
+func pdfImageData(img image.Image) ([]byte, error) {
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(bounds)
+	draw.Draw(rgba, bounds, image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(rgba, bounds, img, bounds.Min, draw.Over)
+
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	row := make([]byte, 0, bounds.Dx()*3)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		row = row[:0]
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			i := rgba.PixOffset(x, y)
+			row = append(row, rgba.Pix[i], rgba.Pix[i+1], rgba.Pix[i+2])
+		}
+		if _, err := zw.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// pdfWriter writes numbered objects and keeps their offsets for the cross-reference table.
+type pdfWriter struct {
+	buf     bytes.Buffer
+	offsets map[int]int
+}
+
+func (w *pdfWriter) header() {
+	w.offsets = make(map[int]int)
+	// The binary comment marks the file as binary for transfer tools.
+	w.buf.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+}
+
+func (w *pdfWriter) object(id int, body string) {
+	w.offsets[id] = w.buf.Len()
+	_, _ = fmt.Fprintf(&w.buf, "%d 0 obj\n%s\nendobj\n", id, body)
+}
+
+func (w *pdfWriter) stream(id int, dict string, data []byte) {
+	w.offsets[id] = w.buf.Len()
+	_, _ = fmt.Fprintf(&w.buf, "%d 0 obj\n<< %s/Length %d >>\nstream\n", id, dict, len(data))
+	w.buf.Write(data)
+	w.buf.WriteString("\nendstream\nendobj\n")
+}
+
+// finish writes the cross-reference table and trailer; size is one more than the highest object number.
+func (w *pdfWriter) finish(size int) []byte {
+	xref := w.buf.Len()
+	_, _ = fmt.Fprintf(&w.buf, "xref\n0 %d\n0000000000 65535 f \n", size)
+	for id := 1; id < size; id++ {
+		_, _ = fmt.Fprintf(&w.buf, "%010d 00000 n \n", w.offsets[id])
+	}
+	_, _ = fmt.Fprintf(&w.buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", size, xref)
+	return w.buf.Bytes()
+}
+
+// pdfNumber formats a number with at most 2 decimals and no trailing zeros.
+func pdfNumber(f float64) string {
+	s := fmt.Sprintf("%.2f", f)
+	return strings.TrimRight(strings.TrimRight(s, "0"), ".")
+}
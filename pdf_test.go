@@ -0,0 +1,143 @@
+package thaiqr_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"github.com/Jdemon/thaiqr"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/image/font/sfnt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// assertValidPDF checks the header, trailer and that every cross-reference entry points at its object.
+func assertValidPDF(t *testing.T, pdf []byte) {
+	t.Helper()
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+
+	startXref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+	assert.NotNil(t, startXref)
+	offset, _ := strconv.Atoi(string(startXref[1]))
+	assert.True(t, bytes.HasPrefix(pdf[offset:], []byte("xref\n")))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(pdf[offset:], -1)
+	for i, entry := range entries {
+		objOffset, _ := strconv.Atoi(string(entry[1]))
+		assert.True(t, bytes.HasPrefix(pdf[objOffset:], []byte(strconv.Itoa(i+1)+" 0 obj\n")), "object %d", i+1)
+	}
+}
+
+// pdfCaptions returns the text of every caption line, decoded through the font's ToUnicode map.
+func pdfCaptions(t *testing.T, pdf []byte) []string {
+	t.Helper()
+	toUnicode := make(map[string]rune)
+	for _, m := range regexp.MustCompile(`<([0-9A-F]{4})> <([0-9A-F]{4})>`).FindAllSubmatch(pdf, -1) {
+		r, _ := strconv.ParseUint(string(m[2]), 16, 16)
+		toUnicode[string(m[1])] = rune(r)
+	}
+
+	var captions []string
+	for _, line := range regexp.MustCompile(`BT /F1 .* ET`).FindAll(pdf, -1) {
+		var caption strings.Builder
+		for _, m := range regexp.MustCompile(`<([0-9A-F]*)> Tj`).FindAllSubmatch(line, -1) {
+			for i := 0; i+4 <= len(m[1]); i += 4 {
+				r, ok := toUnicode[string(m[1][i:i+4])]
+				assert.True(t, ok, "glyph %s has no ToUnicode entry", m[1][i:i+4])
+				caption.WriteRune(r)
+			}
+		}
+		captions = append(captions, caption.String())
+	}
+	return captions
+}
+
+func TestGeneratePDFBillPaymentInvoice(t *testing.T) {
+	item, err := thaiqr.BillPaymentPDFItem("Jdemon Shop (HQ)", thaiqr.PromptPayBillPaymentQRCmd{
+		BillerID: "0105556123456",
+		Ref1:     "INV001",
+		Ref2:     "CUST42",
+		Amount:   "1500",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Jdemon Shop (HQ)", "Amount: 1500.00 THB", "Biller ID: 0105556123456", "Ref1: INV001", "Ref2: CUST42"}, item.Captions)
+
+	pdf, err := thaiqr.GeneratePDFWithThaiQRLogo([]thaiqr.PDFItem{item}, thaiqr.PDFLayoutA5)
+	assert.Nil(t, err)
+	assertValidPDF(t, *pdf)
+	assert.Contains(t, string(*pdf), "/MediaBox [0 0 419.53 595.28]")
+	assert.Equal(t, item.Captions, pdfCaptions(t, *pdf))
+	assert.Equal(t, 1, bytes.Count(*pdf, []byte("/Subtype /Image")))
+}
+
+func TestGeneratePDFLabelSheetPages(t *testing.T) {
+	items := make([]thaiqr.PDFItem, 0, 30)
+	for i := 0; i < 30; i++ {
+		item, err := thaiqr.PromptPayPDFItem("Table "+strconv.Itoa(i+1), thaiqr.PromptPayQRCmd{ProxyID: "0909764856"})
+		assert.Nil(t, err)
+		items = append(items, item)
+	}
+
+	pdf, err := thaiqr.GeneratePDF(items, thaiqr.PDFLayoutLabelSheet,
+		thaiqr.WithRenderOptions(thaiqr.RenderOptions{ModuleSize: 2}))
+	assert.Nil(t, err)
+	assertValidPDF(t, *pdf)
+	assert.Contains(t, string(*pdf), "/Count 2")
+	assert.Equal(t, 30, bytes.Count(*pdf, []byte("/Subtype /Image")))
+	captions := pdfCaptions(t, *pdf)
+	assert.Len(t, captions, 30)
+	assert.Equal(t, "Table 30", captions[29])
+}
+
+func TestGeneratePDFThaiCaption(t *testing.T) {
+	item, err := thaiqr.PromptPayPDFItem("ร้านกาแฟ สุขใจ", thaiqr.PromptPayQRCmd{ProxyID: "0909764856", Amount: "45"})
+	assert.Nil(t, err)
+
+	pdf, err := thaiqr.GeneratePDF([]thaiqr.PDFItem{item}, thaiqr.PDFLayoutA5)
+	assert.Nil(t, err)
+	assertValidPDF(t, *pdf)
+	assert.Equal(t, []string{"ร้านกาแฟ สุขใจ", "Amount: 45.00 THB"}, pdfCaptions(t, *pdf))
+	assert.NotContains(t, string(*pdf), "/Helvetica")
+
+	// The embedded subset must be a TrueType font keeping the outlines of the Thai glyphs shown.
+	stream := regexp.MustCompile(`(?s)/Length1 (\d+) /Filter /FlateDecode /Length (\d+) >>\nstream\n`).FindSubmatchIndex(*pdf)
+	assert.NotNil(t, stream)
+	length1, _ := strconv.Atoi(string((*pdf)[stream[2]:stream[3]]))
+	length, _ := strconv.Atoi(string((*pdf)[stream[4]:stream[5]]))
+	zr, err := zlib.NewReader(bytes.NewReader((*pdf)[stream[1] : stream[1]+length]))
+	assert.Nil(t, err)
+	ttf, err := io.ReadAll(zr)
+	assert.Nil(t, err)
+	assert.Len(t, ttf, length1)
+
+	subset, err := sfnt.Parse(ttf)
+	assert.Nil(t, err)
+	fontFile, err := os.ReadFile("assets/fonts/NotoSansThai-Regular.ttf")
+	assert.Nil(t, err)
+	full, err := sfnt.Parse(fontFile)
+	assert.Nil(t, err)
+	var buf sfnt.Buffer
+	for _, r := range "รก" {
+		g, err := full.GlyphIndex(&buf, r)
+		assert.Nil(t, err)
+		segments, err := subset.LoadGlyph(&buf, g, 1000<<6, nil)
+		assert.Nil(t, err)
+		assert.NotEmpty(t, segments)
+	}
+	assert.Less(t, len(ttf), len(fontFile)/2)
+}
+
+func TestGeneratePDFInvalid(t *testing.T) {
+	_, err := thaiqr.GeneratePDF(nil, thaiqr.PDFLayoutA4)
+	assert.Error(t, err)
+
+	_, err = thaiqr.GeneratePDF([]thaiqr.PDFItem{{Payload: "000201"}}, thaiqr.PDFLayout{PageSize: thaiqr.PageSizeA4})
+	assert.Error(t, err)
+
+	_, err = thaiqr.PromptPayPDFItem("Shop", thaiqr.PromptPayQRCmd{ProxyID: "0909764856", Amount: "abc"})
+	assert.Error(t, err)
+}
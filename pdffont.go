@@ -0,0 +1,348 @@
+package thaiqr
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+	"hash/crc32"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// pdfFont sets PDF captions in Noto Sans Thai, embedded as a composite (Type0) font with
+// Identity-H encoding: shown strings are 2-byte glyph indices. It records the glyphs it shows
+// so only those are embedded, along with their widths and a ToUnicode map for copying text.
+type pdfFont struct {
+	font *opentype.Font
+	buf  sfnt.Buffer
+	used map[sfnt.GlyphIndex]rune
+}
+
+func newPDFFont() (*pdfFont, error) {
+	f, err := loadThaiFont()
+	if err != nil {
+		return nil, err
+	}
+	return &pdfFont{font: f, used: make(map[sfnt.GlyphIndex]rune)}, nil
+}
+
+// glyph returns the glyph for r and its advance width in 1/1000 em.
+func (f *pdfFont) glyph(r rune) (sfnt.GlyphIndex, int) {
+	g, err := f.font.GlyphIndex(&f.buf, r)
+	if err != nil {
+		g = 0
+	}
+	unitsPerEm := int(f.font.UnitsPerEm())
+	advance, err := f.font.GlyphAdvance(&f.buf, g, fixed.I(unitsPerEm), font.HintingNone)
+	if err != nil {
+		return g, 0
+	}
+	return g, advance.Round() * 1000 / unitsPerEm
+}
+
+// width returns the width of s in points.
+func (f *pdfFont) width(s string, fontSize float64) float64 {
+	total := 0
+	for _, r := range s {
+		_, advance := f.glyph(r)
+		total += advance
+	}
+	return float64(total) * fontSize / 1000
+}
+
+// truncate shortens s with an ellipsis so it fits within width points.
+func (f *pdfFont) truncate(s string, fontSize, width float64) string {
+	if f.width(s, fontSize) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && f.width(string(runes)+"…", fontSize) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+// show returns the text operators drawing s. Without the font's shaping tables, a tone mark
+// following an upper vowel would overlap it, so it is raised like on the poster.
+func (f *pdfFont) show(s string, fontSize float64) string {
+	var ops strings.Builder
+	var run strings.Builder
+	flush := func() {
+		if run.Len() > 0 {
+			_, _ = fmt.Fprintf(&ops, "<%s> Tj ", run.String())
+			run.Reset()
+		}
+	}
+
+	var prev rune
+	for _, r := range s {
+		g, _ := f.glyph(r)
+		if _, ok := f.used[g]; !ok {
+			f.used[g] = r
+		}
+		if isThaiToneMark(r) && isThaiUpperVowel(prev) {
+			flush()
+			_, _ = fmt.Fprintf(&ops, "%s Ts <%04X> Tj 0 Ts ", pdfNumber(fontSize*0.22), uint16(g))
+		} else {
+			_, _ = fmt.Fprintf(&run, "%04X", uint16(g))
+		}
+		prev = r
+	}
+	flush()
+	return strings.TrimSuffix(ops.String(), " ")
+}
+
+// glyphs returns the shown glyphs in index order.
+func (f *pdfFont) glyphs() []sfnt.GlyphIndex {
+	glyphs := make([]sfnt.GlyphIndex, 0, len(f.used))
+	for g := range f.used {
+		glyphs = append(glyphs, g)
+	}
+	slices.Sort(glyphs)
+	return glyphs
+}
+
+// write writes the Type0 font as object id and its descendant CIDFont, font descriptor,
+// font file and ToUnicode map as the four objects after first.
+func (f *pdfFont) write(w *pdfWriter, id, first int) error {
+	glyphs := f.glyphs()
+	keep := map[uint16]bool{0: true}
+	for _, g := range glyphs {
+		keep[uint16(g)] = true
+	}
+	subset, err := subsetTrueType(thaiFontTTF, keep)
+	if err != nil {
+		return err
+	}
+	postScriptName, err := f.font.Name(&f.buf, sfnt.NameIDPostScript)
+	if err != nil {
+		return err
+	}
+	// Subset fonts are named with a tag of six capital letters derived from the glyph set.
+	sum := crc32.ChecksumIEEE([]byte(fmt.Sprint(glyphs)))
+	tag := make([]byte, 6)
+	for i := range tag {
+		tag[i] = 'A' + byte(sum%26)
+		sum /= 26
+	}
+	baseFont := string(tag) + "+" + postScriptName
+
+	unitsPerEm := int(f.font.UnitsPerEm())
+	ppem := fixed.I(unitsPerEm)
+	metrics, err := f.font.Metrics(&f.buf, ppem, font.HintingNone)
+	if err != nil {
+		return err
+	}
+	bounds, err := f.font.Bounds(&f.buf, ppem, font.HintingNone)
+	if err != nil {
+		return err
+	}
+	// sfnt measures y downwards, PDF upwards.
+	em := func(v fixed.Int26_6) string { return fmt.Sprint(v.Round() * 1000 / unitsPerEm) }
+
+	widths := make([]string, 0, len(glyphs))
+	toUnicode := make([]string, 0, len(glyphs))
+	for _, g := range glyphs {
+		_, advance := f.glyph(f.used[g])
+		widths = append(widths, fmt.Sprintf("%d [%d]", g, advance))
+		toUnicode = append(toUnicode, fmt.Sprintf("<%04X> <%s>", uint16(g), utf16Hex(f.used[g])))
+	}
+
+	w.object(id, fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+		baseFont, first, first+3))
+	w.object(first, fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /CIDToGIDMap /Identity /W [%s] >>",
+		baseFont, first+1, strings.Join(widths, " ")))
+	w.object(first+1, fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%s %s %s %s] /ItalicAngle 0 /Ascent %s /Descent %s /CapHeight %s /StemV 80 /FontFile2 %d 0 R >>",
+		baseFont, em(bounds.Min.X), em(-bounds.Max.Y), em(bounds.Max.X), em(-bounds.Min.Y),
+		em(metrics.Ascent), em(-metrics.Descent), em(metrics.CapHeight), first+2))
+
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	if _, err := zw.Write(subset); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	w.stream(first+2, fmt.Sprintf("/Length1 %d /Filter /FlateDecode ", len(subset)), compressed.Bytes())
+
+	cmap := "/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n"
+	// A bfchar block holds at most 100 entries.
+	for len(toUnicode) > 0 {
+		n := min(len(toUnicode), 100)
+		cmap += fmt.Sprintf("%d beginbfchar\n%s\nendbfchar\n", n, strings.Join(toUnicode[:n], "\n"))
+		toUnicode = toUnicode[n:]
+	}
+	cmap += "endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend"
+	w.stream(first+3, "", []byte(cmap))
+	return nil
+}
+
+// utf16Hex returns r in UTF-16BE as hexadecimal.
+func utf16Hex(r rune) string {
+	if r < 0x10000 {
+		return fmt.Sprintf("%04X", r)
+	}
+	r -= 0x10000
+	return fmt.Sprintf("%04X%04X", 0xD800+(r>>10), 0xDC00+(r&0x3FF))
+}
+
+// subsetTrueTypeTables are the tables a TrueType font embedded in a PDF needs.
+var subsetTrueTypeTables = []string{"OS/2", "cmap", "cvt ", "fpgm", "glyf", "head", "hhea", "hmtx", "loca", "maxp", "post", "prep"}
+
+// subsetTrueType returns ttf with the outlines of all glyphs but keep, and the glyphs they are
+// composed of, removed. Glyph indices are unchanged.
+func subsetTrueType(ttf []byte, keep map[uint16]bool) ([]byte, error) {
+	errMalformed := errors.New("malformed font")
+	if len(ttf) < 12 {
+		return nil, errMalformed
+	}
+	tables := make(map[string][]byte)
+	for i := 0; i < int(binary.BigEndian.Uint16(ttf[4:])); i++ {
+		entry := 12 + 16*i
+		if entry+16 > len(ttf) {
+			return nil, errMalformed
+		}
+		offset, length := binary.BigEndian.Uint32(ttf[entry+8:]), binary.BigEndian.Uint32(ttf[entry+12:])
+		if uint64(offset)+uint64(length) > uint64(len(ttf)) {
+			return nil, errMalformed
+		}
+		tables[string(ttf[entry:entry+4])] = ttf[offset : offset+length]
+	}
+	head, maxp, loca, glyf := tables["head"], tables["maxp"], tables["loca"], tables["glyf"]
+	if len(head) < 54 || len(maxp) < 6 || glyf == nil {
+		return nil, errMalformed
+	}
+
+	numGlyphs := int(binary.BigEndian.Uint16(maxp[4:]))
+	longLoca := binary.BigEndian.Uint16(head[50:]) == 1
+	if (longLoca && len(loca) < 4*(numGlyphs+1)) || (!longLoca && len(loca) < 2*(numGlyphs+1)) {
+		return nil, errMalformed
+	}
+	glyphData := func(g uint16) []byte {
+		var start, end uint32
+		if longLoca {
+			start, end = binary.BigEndian.Uint32(loca[4*int(g):]), binary.BigEndian.Uint32(loca[4*int(g)+4:])
+		} else {
+			start, end = 2*uint32(binary.BigEndian.Uint16(loca[2*int(g):])), 2*uint32(binary.BigEndian.Uint16(loca[2*int(g)+2:]))
+		}
+		if start > end || end > uint32(len(glyf)) {
+			return nil
+		}
+		return glyf[start:end]
+	}
+
+	// Composite glyphs reference their components, which must be kept too.
+	pending := make([]uint16, 0, len(keep))
+	for g := range keep {
+		pending = append(pending, g)
+	}
+	for len(pending) > 0 {
+		g := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		data := glyphData(g)
+		if len(data) < 10 || int16(binary.BigEndian.Uint16(data)) >= 0 {
+			continue
+		}
+		for i := 10; i+4 <= len(data); {
+			flags, component := binary.BigEndian.Uint16(data[i:]), binary.BigEndian.Uint16(data[i+2:])
+			if int(component) < numGlyphs && !keep[component] {
+				keep[component] = true
+				pending = append(pending, component)
+			}
+			i += 4 + ifThenElse(flags&0x0001 != 0, 4, 2).(int)
+			switch {
+			case flags&0x0008 != 0:
+				i += 2
+			case flags&0x0040 != 0:
+				i += 4
+			case flags&0x0080 != 0:
+				i += 8
+			}
+			if flags&0x0020 == 0 {
+				break
+			}
+		}
+	}
+
+	var newGlyf []byte
+	newLoca := make([]byte, 4*(numGlyphs+1))
+	for g := 0; g < numGlyphs; g++ {
+		if keep[uint16(g)] {
+			newGlyf = append(newGlyf, glyphData(uint16(g))...)
+			for len(newGlyf)%4 != 0 {
+				newGlyf = append(newGlyf, 0)
+			}
+		}
+		binary.BigEndian.PutUint32(newLoca[4*(g+1):], uint32(len(newGlyf)))
+	}
+	tables["glyf"], tables["loca"] = newGlyf, newLoca
+	newHead := slices.Clone(head)
+	binary.BigEndian.PutUint16(newHead[50:], 1)
+	binary.BigEndian.PutUint32(newHead[8:], 0)
+	tables["head"] = newHead
+	// Format 3 of the post table drops the glyph names.
+	if post := tables["post"]; len(post) >= 32 {
+		newPost := slices.Clone(post[:32])
+		binary.BigEndian.PutUint32(newPost, 0x00030000)
+		tables["post"] = newPost
+	}
+
+	tags := make([]string, 0, len(subsetTrueTypeTables))
+	for _, tag := range subsetTrueTypeTables {
+		if _, ok := tables[tag]; ok {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+
+	out := make([]byte, 12+16*len(tags))
+	binary.BigEndian.PutUint32(out, 0x00010000)
+	entrySelector := 0
+	for 1<<(entrySelector+1) <= len(tags) {
+		entrySelector++
+	}
+	binary.BigEndian.PutUint16(out[4:], uint16(len(tags)))
+	binary.BigEndian.PutUint16(out[6:], uint16(16<<entrySelector))
+	binary.BigEndian.PutUint16(out[8:], uint16(entrySelector))
+	binary.BigEndian.PutUint16(out[10:], uint16(16*len(tags)-16<<entrySelector))
+	headOffset := 0
+	for i, tag := range tags {
+		data := tables[tag]
+		entry := 12 + 16*i
+		copy(out[entry:], tag)
+		binary.BigEndian.PutUint32(out[entry+4:], trueTypeChecksum(data))
+		binary.BigEndian.PutUint32(out[entry+8:], uint32(len(out)))
+		binary.BigEndian.PutUint32(out[entry+12:], uint32(len(data)))
+		if tag == "head" {
+			headOffset = len(out)
+		}
+		out = append(out, data...)
+		for len(out)%4 != 0 {
+			out = append(out, 0)
+		}
+	}
+	binary.BigEndian.PutUint32(out[headOffset+8:], 0xB1B0AFBA-trueTypeChecksum(out))
+	return out, nil
+}
+
+// trueTypeChecksum sums data as big-endian 32-bit words, zero padded.
+func trueTypeChecksum(data []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(data); i += 4 {
+		var word [4]byte
+		copy(word[:], data[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+	return sum
+}
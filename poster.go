@@ -0,0 +1,271 @@
+package thaiqr
+
+import (
+	"errors"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"image"
+	"image/color"
+	"strings"
+)
+
+// PosterResolution is the pixel size of a poster. Posters keep the A-series 1:√2 aspect ratio.
+type PosterResolution struct {
+	Width  int
+	Height int
+	// DPI is written to the PNG so print tools size the poster correctly. 0 omits it.
+	DPI int
+}
+
+var (
+	// PosterResolutionScreen suits web pages, chat apps and customer-facing displays.
+	PosterResolutionScreen = PosterResolution{Width: 1080, Height: 1527}
+	// PosterResolutionPrintA5 prints an A5 table tent or counter standee at 300 DPI.
+	PosterResolutionPrintA5 = PosterResolution{Width: 1748, Height: 2480, DPI: 300}
+	// PosterResolutionPrintA4 prints an A4 poster at 300 DPI.
+	PosterResolutionPrintA4 = PosterResolution{Width: 2480, Height: 3508, DPI: 300}
+)
+
+// PosterFields are the display fields printed around the QR code. Thai script is supported.
+type PosterFields struct {
+	MerchantName string
+	AccountName  string
+	// Amount is optional and printed in baht, e.g. "150" or "150.00".
+	Amount string
+	// PromptPayLogo is drawn under the header. A PromptPay wordmark is drawn when it is nil.
+	PromptPayLogo image.Image
+}
+
+var (
+	posterNavy  = color.RGBA{R: 0x11, G: 0x35, B: 0x66, A: 0xff}
+	posterBlue  = color.RGBA{R: 0x00, G: 0x56, B: 0xa3, A: 0xff}
+	posterGrey  = color.RGBA{R: 0x4a, G: 0x4a, B: 0x4a, A: 0xff}
+	posterWhite = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+)
+
+// GeneratePoster generates a PNG Thai QR Payment poster: the Thai QR Payment header, the PromptPay
+// logo, the QR code with the Thai QR logo, or the logo given by WithLogo or WithLogoFS, and the merchant
+// name, account name and amount. The error correction level of WithRenderOptions is honoured; size and
+// DPI follow the resolution.
+func GeneratePoster(payload string, fields PosterFields, resolution PosterResolution, opts ...Option) (*[]byte, error) {
+	img, err := RenderPoster(payload, fields, resolution, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	posterBytes, err := encodePNG(img, resolution.DPI)
+	if err != nil {
+		return nil, err
+	}
+	return &posterBytes, nil
+}
+
+// RenderPoster renders the poster GeneratePoster encodes, e.g. to compose it into a larger artwork.
+func RenderPoster(payload string, fields PosterFields, resolution PosterResolution, opts ...Option) (*image.NRGBA, error) {
+	if resolution.Width <= 0 || resolution.Height <= 0 {
+		return nil, errors.New("invalid poster resolution")
+	}
+	amount := ""
+	if strings.TrimSpace(fields.Amount) != "" {
+		formatted, err := formatAmount(strings.TrimSpace(fields.Amount))
+		if err != nil {
+			return nil, err
+		}
+		amount = formatted
+	}
+	posterFace, err := loadThaiFont()
+	if err != nil {
+		return nil, err
+	}
+
+	o := newOptions(opts)
+	logo, err := o.loadLogo()
+	if err != nil {
+		return nil, err
+	}
+
+	// Layout is in units of 1% of the width, so every resolution renders the same design.
+	width, height := resolution.Width, resolution.Height
+	unit := float64(width) / 100
+	px := func(units float64) int {
+		return int(units * unit)
+	}
+	p := &posterCanvas{img: image.NewNRGBA(image.Rect(0, 0, width, height)), font: posterFace}
+	p.fill(p.img.Rect, posterWhite)
+
+	// Header band: Thai QR logo followed by "THAI QR PAYMENT".
+	headerHeight := px(14)
+	p.fill(image.Rect(0, 0, width, headerHeight), posterNavy)
+	thaiQRLogo, err := ThaiQRLogo()
+	if err != nil {
+		return nil, err
+	}
+	headerLogo := scaleToHeight(thaiQRLogo, px(8))
+	headerText := "THAI QR PAYMENT"
+	headerSize := 6 * unit
+	headerWidth, err := p.measure(headerText, headerSize)
+	if err != nil {
+		return nil, err
+	}
+	left := (width - headerLogo.Bounds().Dx() - px(2) - headerWidth) / 2
+	p.paste(headerLogo, image.Pt(left, (headerHeight-headerLogo.Bounds().Dy())/2))
+	if err := p.text(headerText, headerSize, left+headerLogo.Bounds().Dx()+px(2), px(9.2), posterWhite); err != nil {
+		return nil, err
+	}
+
+	// PromptPay logo, or its wordmark.
+	if fields.PromptPayLogo != nil {
+		promptPayLogo := scaleToHeight(fields.PromptPayLogo, px(9))
+		p.paste(promptPayLogo, image.Pt((width-promptPayLogo.Bounds().Dx())/2, px(17)))
+	} else if err := p.centeredText("PromptPay", 8, px(25), posterBlue, unit); err != nil {
+		return nil, err
+	}
+
+	// QR code with its centre logo.
+	ro := o.render
+	ro.Size = px(64)
+	ro.ModuleSize = 0
+	ro.Transparent = false
+	ro = ro.normalize()
+	qrCode, err := renderQR(payload, ro, logo, o.verify)
+	if err != nil {
+		return nil, err
+	}
+	p.paste(qrCode, image.Pt((width-qrCode.Bounds().Dx())/2, px(29)))
+
+	// Display fields under the QR code.
+	qrBottom := px(29) + qrCode.Bounds().Dy()
+	if fields.MerchantName != "" {
+		if err := p.centeredText(fields.MerchantName, 5.5, qrBottom+px(8), posterNavy, unit); err != nil {
+			return nil, err
+		}
+	}
+	if fields.AccountName != "" {
+		if err := p.centeredText("ชื่อบัญชี: "+fields.AccountName, 3.8, qrBottom+px(14), posterGrey, unit); err != nil {
+			return nil, err
+		}
+	}
+	if amount != "" {
+		if err := p.centeredText("จำนวนเงิน "+amount+" บาท", 5, qrBottom+px(22), posterBlue, unit); err != nil {
+			return nil, err
+		}
+	}
+
+	// Footer band.
+	footerHeight := px(8)
+	p.fill(image.Rect(0, height-footerHeight, width, height), posterNavy)
+	if err := p.centeredText("สแกนเพื่อชำระเงิน | Scan to pay", 3.2, height-footerHeight+px(5.2), posterWhite, unit); err != nil {
+		return nil, err
+	}
+
+	return p.img, nil
+}
+
+// posterCanvas draws the poster elements onto img.
+type posterCanvas struct {
+	img  *image.NRGBA
+	font *opentype.Font
+}
+
+func (p *posterCanvas) fill(r image.Rectangle, c color.Color) {
+	draw.Draw(p.img, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+func (p *posterCanvas) paste(src image.Image, at image.Point) {
+	r := src.Bounds().Sub(src.Bounds().Min).Add(at)
+	draw.Draw(p.img, r, src, src.Bounds().Min, draw.Over)
+}
+
+func (p *posterCanvas) face(size float64) (font.Face, error) {
+	return opentype.NewFace(p.font, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingNone})
+}
+
+// measure returns the advance width of s in pixels at the given size.
+func (p *posterCanvas) measure(s string, size float64) (int, error) {
+	face, err := p.face(size)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = face.Close()
+	}()
+	return font.MeasureString(face, s).Ceil(), nil
+}
+
+// text draws s with its baseline starting at (x, baseline). The font is drawn without OpenType
+// shaping, so a Thai tone mark following an upper vowel is lifted above it by hand.
+func (p *posterCanvas) text(s string, size float64, x, baseline int, c color.Color) error {
+	face, err := p.face(size)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = face.Close()
+	}()
+	drawer := font.Drawer{Dst: p.img, Src: image.NewUniform(c), Face: face, Dot: fixed.P(x, baseline)}
+	lift := fixed.Int26_6(size * 0.22 * 64)
+	var prev rune
+	for _, r := range s {
+		if isThaiToneMark(r) && isThaiUpperVowel(prev) {
+			drawer.Dot.Y -= lift
+			drawer.DrawString(string(r))
+			drawer.Dot.Y += lift
+		} else {
+			drawer.DrawString(string(r))
+		}
+		prev = r
+	}
+	return nil
+}
+
+// isThaiToneMark reports whether r is one of the Thai tone marks or the thanthakhat.
+func isThaiToneMark(r rune) bool {
+	return r >= '\u0E48' && r <= '\u0E4C'
+}
+
+// isThaiUpperVowel reports whether r is a Thai vowel sign written above the consonant.
+func isThaiUpperVowel(r rune) bool {
+	return r == '\u0E31' || (r >= '\u0E34' && r <= '\u0E37') || r == '\u0E47'
+}
+
+// centeredText draws s centred horizontally, shortened with an ellipsis to fit within 90% of the width.
+// size is in layout units.
+func (p *posterCanvas) centeredText(s string, size float64, baseline int, c color.Color, unit float64) error {
+	size *= unit
+	maxWidth := int(90 * unit)
+	width, err := p.measure(s, size)
+	if err != nil {
+		return err
+	}
+	if width > maxWidth {
+		runes := []rune(s)
+		for len(runes) > 0 {
+			if width, err = p.measure(string(runes)+"…", size); err != nil {
+				return err
+			}
+			if width <= maxWidth {
+				break
+			}
+			runes = runes[:len(runes)-1]
+		}
+		s = string(runes) + "…"
+		if width, err = p.measure(s, size); err != nil {
+			return err
+		}
+	}
+	return p.text(s, size, (p.img.Rect.Dx()-width)/2, baseline, c)
+}
+
+// scaleToHeight resizes img to the given height, keeping its aspect ratio.
+func scaleToHeight(img image.Image, height int) image.Image {
+	b := img.Bounds()
+	if b.Dy() == 0 || height <= 0 {
+		return img
+	}
+	width := max(b.Dx()*height/b.Dy(), 1)
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Over, nil)
+	return dst
+}
@@ -0,0 +1,80 @@
+package thaiqr_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/Jdemon/thaiqr"
+	"github.com/stretchr/testify/assert"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestGeneratePosterScreen(t *testing.T) {
+	posterBytes, err := thaiqr.GeneratePoster(logoTestPayload, thaiqr.PosterFields{
+		MerchantName: "ร้านกาแฟ ชุมชนดี",
+		AccountName:  "นาย สมชาย ใจดี",
+		Amount:       "150",
+	}, thaiqr.PosterResolutionScreen)
+	assert.Nil(t, err)
+
+	img, err := png.Decode(bytes.NewReader(*posterBytes))
+	assert.Nil(t, err)
+	assert.Equal(t, image.Rect(0, 0, 1080, 1527), img.Bounds())
+	// navy header and footer bands around a white body
+	navy := color.NRGBA{R: 0x11, G: 0x35, B: 0x66, A: 0xff}
+	assert.Equal(t, navy, color.NRGBAModel.Convert(img.At(5, 5)))
+	assert.Equal(t, navy, color.NRGBAModel.Convert(img.At(5, 1520)))
+	assert.Equal(t, color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}, color.NRGBAModel.Convert(img.At(5, 200)))
+}
+
+func TestRenderPosterDisplayFields(t *testing.T) {
+	// rows of the layout holding the merchant name, account name and amount lines
+	inkRows := func(img *image.NRGBA, from, to int) int {
+		rows := 0
+		for y := from; y < to; y++ {
+			for x := 0; x < img.Bounds().Dx(); x++ {
+				if img.NRGBAAt(x, y).R < 0x80 {
+					rows++
+					break
+				}
+			}
+		}
+		return rows
+	}
+
+	blank, err := thaiqr.RenderPoster(logoTestPayload, thaiqr.PosterFields{}, thaiqr.PosterResolutionScreen)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, inkRows(blank, 1000, 1400))
+
+	filled, err := thaiqr.RenderPoster(logoTestPayload, thaiqr.PosterFields{
+		MerchantName: strings.Repeat("ร้านค้าชื่อยาวมาก ", 10),
+		AccountName:  "บริษัท ตัวอย่าง จำกัด",
+		Amount:       "1500.50",
+	}, thaiqr.PosterResolutionScreen)
+	assert.Nil(t, err)
+	assert.Greater(t, inkRows(filled, 1000, 1400), 100)
+}
+
+func TestGeneratePosterPrint(t *testing.T) {
+	posterBytes, err := thaiqr.GeneratePoster(logoTestPayload, thaiqr.PosterFields{MerchantName: "Jdemon Shop"},
+		thaiqr.PosterResolutionPrintA5, thaiqr.WithRenderOptions(thaiqr.RenderOptions{ErrorCorrection: thaiqr.ErrorCorrectionQuartile}))
+	assert.Nil(t, err)
+
+	data := *posterBytes
+	assert.Equal(t, []byte("pHYs"), data[37:41])
+	assert.Equal(t, uint32(11811), binary.BigEndian.Uint32(data[41:45]))
+	img, err := png.Decode(bytes.NewReader(data))
+	assert.Nil(t, err)
+	assert.Equal(t, image.Rect(0, 0, 1748, 2480), img.Bounds())
+}
+
+func TestGeneratePosterInvalid(t *testing.T) {
+	_, err := thaiqr.GeneratePoster(logoTestPayload, thaiqr.PosterFields{Amount: "abc"}, thaiqr.PosterResolutionScreen)
+	assert.Error(t, err)
+
+	_, err = thaiqr.GeneratePoster(logoTestPayload, thaiqr.PosterFields{}, thaiqr.PosterResolution{})
+	assert.Error(t, err)
+}
@@ -2,7 +2,6 @@ package thaiqr
 
 import (
 	"errors"
-	"fmt"
 	"slices"
 	"strings"
 )
@@ -32,6 +31,9 @@ const (
 	TransactionCurrencyTHB   = "764"
 	CountryCodeTH            = "TH"
 
+	// GUIDPromptPayBillPaymentCrossBorder Bill Payment Tag 30 for cross-border merchants
+	GUIDPromptPayBillPaymentCrossBorder = "A000000677010113"
+
 	ProxyTypeEWalletID   = "EWALLETID"
 	ProxyTypeNatID       = "NATID"
 	ProxyTypeBankAccount = "BANKACCOUNT"
@@ -78,6 +80,8 @@ type PromptPayQRResults struct {
 	PointOfInitiationMethod string            `json:"pointOfInitiationMethod"`
 	CreditTransfer          *CreditTransfer   `json:"creditTransfer,omitempty"`
 	BillPayment             *BillPayment      `json:"billPayment,omitempty"`
+	MerchantAccounts        []MerchantAccount `json:"merchantAccounts,omitempty"`
+	PaymentNetwork          PaymentNetwork    `json:"paymentNetwork,omitempty"`
 	MerchantCategoryCode    string            `json:"merchantCategoryCode,omitempty"`
 	TransactionCurrency     string            `json:"transactionCurrency"`
 	TransactionCurrencyCode string            `json:"transactionCurrencyCode"`
@@ -114,8 +118,38 @@ type AdditionalFields struct {
 	Segments   *[]Segment `json:"segments,omitempty"`
 }
 
+// QRKind describes which kind of merchant presented QR a PromptPayQRResults holds.
+type QRKind string
+
+const (
+	QRKindCreditTransfer QRKind = "CREDIT_TRANSFER"
+	QRKindBillPayment    QRKind = "BILL_PAYMENT"
+	QRKindCrossBorder    QRKind = "CROSS_BORDER"
+	QRKindOther          QRKind = "OTHER"
+)
+
+// Kind reports the kind of QR scanned, based on the PromptPay templates present in the payload.
+// Payloads of other networks, whose templates end up in MerchantAccounts, are QRKindOther.
+func (r *PromptPayQRResults) Kind() QRKind {
+	if r.CreditTransfer == nil && r.BillPayment == nil {
+		return QRKindOther
+	}
+	if r.TransactionCurrency != TransactionCurrencyTHB || r.CountryCode != CountryCodeTH ||
+		(r.BillPayment != nil && r.BillPayment.AID == GUIDPromptPayBillPaymentCrossBorder) {
+		return QRKindCrossBorder
+	}
+	if r.CreditTransfer != nil {
+		return QRKindCreditTransfer
+	}
+	return QRKindBillPayment
+}
+
 // PromptPayQR represents a PromptPay QR code generator.
-type PromptPayQR struct{}
+type PromptPayQR struct {
+	// Strict rejects payloads with malformed nested templates or carrying
+	// both a credit transfer (tag 29) and a bill payment (tag 30) template.
+	Strict bool
+}
 
 // NewPromptPayQR returns a new PromptPayQR instance.
 func NewPromptPayQR() *PromptPayQR {
@@ -124,16 +158,21 @@ func NewPromptPayQR() *PromptPayQR {
 
 // GeneratePayload generates a PromptPay QR code payload.
 func (qr *PromptPayQR) GeneratePayload(cmd PromptPayQRCmd) (string, error) {
-	proxyID := sanitizeTarget(cmd.ProxyID)
-	tagProxyType := determineTargetType(cmd.ProxyType)
-
-	merchantInfoData := []string{
-		formatField(BOTIDCreditTransferAID, GUIDPromptPay),
-		formatField(tagProxyType, formatTarget(proxyID)),
+	proxyID := formatTarget(sanitizeTarget(cmd.ProxyID))
+	creditTransfer := &CreditTransfer{AID: GUIDPromptPay, OTA: strings.TrimSpace(cmd.OTA)}
+	switch determineTargetType(cmd.ProxyType) {
+	case BOTIDMerchantEWalletID:
+		creditTransfer.EWalletID = proxyID
+	case BOTIDMerchantNationalID:
+		creditTransfer.NationalID = proxyID
+	case BOTIDMerchantBankAccount:
+		creditTransfer.BankAccount = proxyID
+	default:
+		creditTransfer.MSISDN = proxyID
 	}
-
-	if strings.TrimSpace(cmd.OTA) != "" {
-		merchantInfoData = append(merchantInfoData, formatField(BOTIDMerchantOTA, cmd.OTA))
+	merchantInfo, err := PromptPayCreditTransferScheme.EncodeTemplate(creditTransfer)
+	if err != nil {
+		return "", err
 	}
 
 	amount := strings.TrimSpace(cmd.Amount)
@@ -143,25 +182,21 @@ func (qr *PromptPayQR) GeneratePayload(cmd PromptPayQRCmd) (string, error) {
 		currencyNo = currencyCode[cmd.CurrencyCode]
 	}
 
-	data := []string{
-		formatField(IDPayloadFormat, PayloadFormatEMVQRCPSMerchantPresentedMode),
-		formatField(IDPOIMethod, ifThenElse(amount != "", POIMethodDynamic, POIMethodStatic).(string)),
-		formatField(IDMerchantInformationBOT, serialize(merchantInfoData)),
-		formatField(IDTransactionCurrency, currencyNo),
-		formatField(IDCountryCode, ifThenElse(cmd.CountryCode != "", cmd.CountryCode, CountryCodeTH).(string)),
-	}
+	data := &payloadBuilder{}
+	data.field(IDPayloadFormat, PayloadFormatEMVQRCPSMerchantPresentedMode)
+	data.field(IDPOIMethod, ifThenElse(amount != "", POIMethodDynamic, POIMethodStatic).(string))
+	data.raw(merchantInfo...)
+	data.field(IDTransactionCurrency, currencyNo)
+	data.field(IDCountryCode, ifThenElse(cmd.CountryCode != "", cmd.CountryCode, CountryCodeTH).(string))
 	if amount != "" {
 		amountFormat, err := formatAmount(amount)
 		if err != nil {
 			return "", err
 		}
-		data = append(data, formatField(IDTransactionAmount, amountFormat))
+		data.field(IDTransactionAmount, amountFormat)
 	}
 
-	dataToCrc := fmt.Sprintf("%s%s%s", serialize(data), IDCRC, "04")
-	data = append(data, formatField(IDCRC, checksum([]byte(dataToCrc))))
-
-	return serialize(data), nil
+	return data.withChecksum(IDCRC)
 }
 
 // GenerateBillPaymentPayload generates a PromptPay bill payment QR code payload.
@@ -169,45 +204,44 @@ func (qr *PromptPayQR) GenerateBillPaymentPayload(cmd PromptPayBillPaymentQRCmd)
 	billerID := sanitizeTarget(cmd.BillerID)
 	amount := strings.TrimSpace(cmd.Amount)
 
-	data := []string{
-		formatField(IDPayloadFormat, PayloadFormatEMVQRCPSMerchantPresentedMode),
-		formatField(IDPOIMethod, ifThenElse(amount != "", POIMethodDynamic, POIMethodStatic).(string)),
-		formatField(IDMerchantInformationBOTBillPayment, serialize([]string{
-			formatField(BOTIDBillPaymentAID, GUIDPromptPayBillPayment),
-			formatField(BOTIDBillPaymentBillerID, billerID),
-			formatField(BOTIDBillPaymentRef1, cmd.Ref1),
-			formatField(BOTIDBillPaymentRef2, cmd.Ref2),
-		})),
+	billPayment, err := PromptPayBillPaymentScheme.EncodeTemplate(&BillPayment{
+		AID:        GUIDPromptPayBillPayment,
+		BillerID:   billerID,
+		Reference1: cmd.Ref1,
+		Reference2: cmd.Ref2,
+	})
+	if err != nil {
+		return "", err
 	}
 
+	data := &payloadBuilder{}
+	data.field(IDPayloadFormat, PayloadFormatEMVQRCPSMerchantPresentedMode)
+	data.field(IDPOIMethod, ifThenElse(amount != "", POIMethodDynamic, POIMethodStatic).(string))
+	data.raw(billPayment...)
+
 	currencyNo := TransactionCurrencyTHB
 	if cmd.CurrencyCode != "" && currencyCode[cmd.CurrencyCode] != "" {
 		currencyNo = currencyCode[cmd.CurrencyCode]
 	}
-	data = append(data, formatField(IDTransactionCurrency, currencyNo))
+	data.field(IDTransactionCurrency, currencyNo)
 
 	if amount != "" {
 		amountFormat, err := formatAmount(amount)
 		if err != nil {
 			return "", err
 		}
-		data = append(data, formatField(IDTransactionAmount, amountFormat))
+		data.field(IDTransactionAmount, amountFormat)
 	}
 
-	data = append(data, formatField(IDCountryCode, ifThenElse(cmd.CountryCode != "", cmd.CountryCode, CountryCodeTH).(string)))
+	data.field(IDCountryCode, ifThenElse(cmd.CountryCode != "", cmd.CountryCode, CountryCodeTH).(string))
 
 	if strings.TrimSpace(cmd.TerminalID) != "" {
-		data = append(data,
-			formatField(IDAdditionalFields, serialize([]string{
-				formatField(BOTIDTag62TerminalID, cmd.TerminalID),
-			})),
-		)
+		additionalData := &payloadBuilder{}
+		additionalData.field(BOTIDTag62TerminalID, cmd.TerminalID)
+		data.template(IDAdditionalFields, additionalData)
 	}
 
-	dataToCrc := fmt.Sprintf("%s%s%s", serialize(data), IDCRC, "04")
-	data = append(data, formatField(IDCRC, checksum([]byte(dataToCrc))))
-
-	return serialize(data), nil
+	return data.withChecksum(IDCRC)
 }
 
 // determineTargetType determines the type of the target based on its length.
@@ -246,57 +280,72 @@ func (qr *PromptPayQR) Reader(data string) (*PromptPayQRResults, error) {
 	if !slices.Contains([]string{POIMethodStatic, POIMethodDynamic}, poiMethod) {
 		return nil, invalidFormat()
 	}
-	creditTransferData := qrFields[IDMerchantInformationBOT]
-	billPaymentData := qrFields[IDMerchantInformationBOTBillPayment]
-
-	creditTransferFields, creditTransferSegments, _ := deserialize(creditTransferData)
-	billPaymentFields, billPaymentSegments, _ := deserialize(billPaymentData)
+	if qr.Strict {
+		_, hasCreditTransfer := qrFields[IDMerchantInformationBOT]
+		_, hasBillPayment := qrFields[IDMerchantInformationBOTBillPayment]
+		if hasCreditTransfer && hasBillPayment {
+			return nil, errors.New("both credit transfer and bill payment present")
+		}
+	}
 
-	merchantCategoryCode := qrFields[IDMerchantCategoryCode]
-	transactionCurrency := qrFields[IDTransactionCurrency]
-	transactionAmount := qrFields[IDTransactionAmount]
-	merchantName := qrFields[IDMerchantName]
-	merchantCity := qrFields[IDMerchantCity]
-	postalCode := qrFields[IDPostalCode]
 	countryCode := qrFields[IDCountryCode]
 	if len(countryCode) != 2 {
 		return nil, invalidFormat()
 	}
-	additionalData := qrFields[IDAdditionalFields]
-	additionalFields, additionalSegments, _ := deserialize(additionalData)
+	transactionCurrency := qrFields[IDTransactionCurrency]
 
-	return &PromptPayQRResults{
+	results := &PromptPayQRResults{
 		PayloadFormatIndicator:  payloadFormatIndicator,
 		PointOfInitiationMethod: poiMethod,
-		CreditTransfer: &CreditTransfer{
-			AID:         creditTransferFields[BOTIDCreditTransferAID],
-			MSISDN:      creditTransferFields[BOTIDMerchantMSISDN],
-			NationalID:  creditTransferFields[BOTIDMerchantNationalID],
-			EWalletID:   creditTransferFields[BOTIDMerchantEWalletID],
-			BankAccount: creditTransferFields[BOTIDMerchantBankAccount],
-			OTA:         creditTransferFields[BOTIDMerchantOTA],
-			Segments:    &creditTransferSegments,
-		},
-		BillPayment: &BillPayment{
-			AID:        billPaymentFields[BOTIDBillPaymentAID],
-			BillerID:   billPaymentFields[BOTIDBillPaymentBillerID],
-			Reference1: billPaymentFields[BOTIDBillPaymentRef1],
-			Reference2: billPaymentFields[BOTIDBillPaymentRef2],
-			Segments:   &billPaymentSegments,
-		},
-		MerchantCategoryCode:    merchantCategoryCode,
+		MerchantCategoryCode:    qrFields[IDMerchantCategoryCode],
 		TransactionCurrency:     transactionCurrency,
 		TransactionCurrencyCode: GetCurrencyCode(transactionCurrency),
-		TransactionAmount:       transactionAmount,
+		TransactionAmount:       qrFields[IDTransactionAmount],
 		CountryCode:             countryCode,
-		MerchantName:            merchantName,
-		MerchantCity:            merchantCity,
-		PostalCode:              postalCode,
-		AdditionalFields: &AdditionalFields{
+		MerchantName:            qrFields[IDMerchantName],
+		MerchantCity:            qrFields[IDMerchantCity],
+		PostalCode:              qrFields[IDPostalCode],
+		CRC:                     qrFields[IDCRC],
+		Segments:                &qrSegments,
+	}
+
+	// Tags 29 and 30 are only PromptPay templates when they carry a PromptPay AID; other
+	// networks, such as Bakong, reuse them and are left to MerchantAccounts.
+	if creditTransferData, ok := qrFields[IDMerchantInformationBOT]; ok {
+		creditTransfer, err := decodeCreditTransfer(creditTransferData)
+		if err != nil && qr.Strict {
+			return nil, err
+		}
+		if strings.HasPrefix(creditTransfer.AID, GUIDPromptPayPrefix) {
+			results.CreditTransfer = creditTransfer
+		}
+	}
+
+	if billPaymentData, ok := qrFields[IDMerchantInformationBOTBillPayment]; ok {
+		billPayment, err := decodeBillPayment(billPaymentData)
+		if err != nil && qr.Strict {
+			return nil, err
+		}
+		if strings.HasPrefix(billPayment.AID, GUIDPromptPayPrefix) {
+			results.BillPayment = billPayment
+		}
+	}
+
+	if additionalData, ok := qrFields[IDAdditionalFields]; ok {
+		additionalFields, additionalSegments, err := deserialize(additionalData)
+		if err != nil && qr.Strict {
+			return nil, err
+		}
+		results.AdditionalFields = &AdditionalFields{
 			TerminalID: additionalFields[BOTIDTag62TerminalID],
 			Segments:   &additionalSegments,
-		},
-		CRC:      qrFields[IDCRC],
-		Segments: &qrSegments,
-	}, nil
+		}
+	}
+
+	if accounts := decodeMerchantAccounts(qrSegments); len(accounts) > 0 {
+		results.MerchantAccounts = accounts
+	}
+	results.PaymentNetwork = detectPaymentNetwork(results)
+
+	return results, nil
 }
@@ -2,9 +2,8 @@ package thaiqr_test
 
 import (
 	"github.com/Jdemon/thaiqr"
-	"testing"
-
 	"github.com/stretchr/testify/assert"
+	"testing"
 )
 
 func TestGenerateBillPaymentPayloadMustValid(t *testing.T) {
@@ -206,3 +205,89 @@ func TestVerifyPayloadChecksum(t *testing.T) {
 	isValid := thaiqr.VerifyPayloadChecksum("00020101021229390016A000000677010111031500499901428007653037645802TH540510.0063046D71")
 	assert.True(t, isValid)
 }
+
+func TestReaderPopulatesOnlyPresentTemplates(t *testing.T) {
+	qr := thaiqr.NewPromptPayQR()
+
+	result, err := qr.Reader("00020101021229370016A0000006770101110113006690976485653037645802TH540510.006304CF65")
+	assert.Nil(t, err)
+	assert.NotNil(t, result.CreditTransfer)
+	assert.Nil(t, result.BillPayment)
+	assert.Nil(t, result.AdditionalFields)
+	assert.Equal(t, thaiqr.QRKindCreditTransfer, result.Kind())
+
+	result, err = qr.Reader("00020101021230570016A00000067701011201153110400394751010206REF0010304REF253037645406555.555802TH62100706SCB001630437C6")
+	assert.Nil(t, err)
+	assert.Nil(t, result.CreditTransfer)
+	assert.NotNil(t, result.BillPayment)
+	assert.NotNil(t, result.AdditionalFields)
+	assert.Equal(t, thaiqr.QRKindBillPayment, result.Kind())
+}
+
+func TestReaderKindCrossBorderAndOther(t *testing.T) {
+	qr := thaiqr.NewPromptPayQR()
+
+	payload, err := qr.GeneratePayload(thaiqr.PromptPayQRCmd{
+		ProxyID:      "0909764856",
+		ProxyType:    thaiqr.ProxyTypeMsisdn,
+		CurrencyCode: "SGD",
+	})
+	assert.Nil(t, err)
+	result, err := qr.Reader(payload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.QRKindCrossBorder, result.Kind())
+
+	result, err = qr.Reader("00020101021126330009SG.PAYNOW010100211+658123456753037025802SG5902AB6009Singapore630495CE")
+	assert.Nil(t, err)
+	assert.Nil(t, result.CreditTransfer)
+	assert.Nil(t, result.BillPayment)
+	assert.Equal(t, thaiqr.QRKindOther, result.Kind())
+}
+
+func TestReaderKHQRIsNotPromptPay(t *testing.T) {
+	// Bakong carries its account ID in tag 29 without a PromptPay AID
+	result, err := thaiqr.NewPromptPayQR().Reader("00020101021129140010sokha@aclb5204599953031165802KH5905SOKHA6010Phnom Penh6304B6EC")
+	assert.Nil(t, err)
+	assert.Nil(t, result.CreditTransfer)
+	assert.Nil(t, result.BillPayment)
+	assert.Equal(t, thaiqr.QRKindOther, result.Kind())
+	assert.Equal(t, thaiqr.PaymentNetworkBakong, result.PaymentNetwork)
+	if assert.Len(t, result.MerchantAccounts, 1) {
+		assert.Equal(t, thaiqr.IDMerchantInformationBOT, result.MerchantAccounts[0].ID)
+		assert.Equal(t, "sokha@aclb", result.MerchantAccounts[0].GUID)
+	}
+}
+
+func TestReaderStrictRejectsBothTemplates(t *testing.T) {
+	payload := "00020101021129370016A0000006770101110113006690976485630270016A000000677010112010312353037645802TH6304E806"
+
+	_, err := thaiqr.NewPromptPayQR().Reader(payload)
+	assert.Nil(t, err)
+
+	strict := &thaiqr.PromptPayQR{Strict: true}
+	_, err = strict.Reader(payload)
+	assert.Error(t, err)
+
+	// an empty bill payment template still counts as present
+	_, err = strict.Reader("00020101021129370016A00000067701011101130066909764856300053037645802TH630458D6")
+	assert.EqualError(t, err, "both credit transfer and bill payment present")
+}
+
+func TestGeneratePromptPayLargeAmount(t *testing.T) {
+	// the poster formats its amount caption with the same formatter
+	payload, err := thaiqr.NewPromptPayQR().GeneratePayload(thaiqr.PromptPayQRCmd{ProxyID: "0909764856", Amount: "1234567.89"})
+	assert.Nil(t, err)
+	assert.Contains(t, payload, "54101234567.89")
+}
+
+func TestGeneratePromptPayIgnoresNonASEANCurrency(t *testing.T) {
+	payload, err := thaiqr.NewPromptPayQR().GeneratePayload(thaiqr.PromptPayQRCmd{
+		ProxyID:      "0909764856",
+		ProxyType:    thaiqr.ProxyTypeMsisdn,
+		Amount:       "10",
+		CurrencyCode: "USD",
+	})
+	assert.Nil(t, err)
+	assert.Contains(t, payload, "5303764")
+	assert.NotContains(t, payload, "5303840")
+}
@@ -0,0 +1,177 @@
+package thaiqr
+
+import (
+	"errors"
+	"slices"
+)
+
+const (
+	SchemeNamePromptPayCreditTransfer = "PROMPTPAY_CREDIT_TRANSFER"
+	SchemeNamePromptPayBillPayment    = "PROMPTPAY_BILL_PAYMENT"
+)
+
+var (
+	// PromptPayCreditTransferScheme handles the PromptPay credit transfer template (tag 29).
+	// Its templates are *CreditTransfer values.
+	PromptPayCreditTransferScheme Scheme = promptPayCreditTransferScheme{}
+	// PromptPayBillPaymentScheme handles the PromptPay bill payment template (tag 30).
+	// Its templates are *BillPayment values.
+	PromptPayBillPaymentScheme Scheme = promptPayBillPaymentScheme{}
+)
+
+type promptPayCreditTransferScheme struct{}
+
+func (promptPayCreditTransferScheme) Name() string {
+	return SchemeNamePromptPayCreditTransfer
+}
+
+func (promptPayCreditTransferScheme) Detect(fields map[string]string, _ []Segment) bool {
+	return isPromptPayTemplate(fields[IDMerchantInformationBOT])
+}
+
+func (s promptPayCreditTransferScheme) Validate(fields map[string]string) error {
+	creditTransfer, err := s.DecodeTemplate(fields)
+	if err != nil {
+		return err
+	}
+	ct := creditTransfer.(*CreditTransfer)
+	if ct.AID != GUIDPromptPay {
+		return errors.New("invalid credit transfer aid")
+	}
+	proxies := 0
+	for _, proxy := range []string{ct.MSISDN, ct.NationalID, ct.EWalletID, ct.BankAccount} {
+		if proxy != "" {
+			proxies++
+		}
+	}
+	if proxies != 1 {
+		return errors.New("credit transfer must carry exactly one proxy")
+	}
+	return nil
+}
+
+func (promptPayCreditTransferScheme) DecodeTemplate(fields map[string]string) (any, error) {
+	creditTransfer, err := decodeCreditTransfer(fields[IDMerchantInformationBOT])
+	if err != nil {
+		return nil, err
+	}
+	return creditTransfer, nil
+}
+
+func (promptPayCreditTransferScheme) EncodeTemplate(template any) ([]string, error) {
+	creditTransfer, ok := template.(*CreditTransfer)
+	if !ok || creditTransfer == nil {
+		return nil, errors.New("credit transfer template required")
+	}
+	b := &payloadBuilder{}
+	b.template(IDMerchantInformationBOT, encodeCreditTransfer(creditTransfer))
+	field, err := b.string()
+	if err != nil {
+		return nil, err
+	}
+	return []string{field}, nil
+}
+
+type promptPayBillPaymentScheme struct{}
+
+func (promptPayBillPaymentScheme) Name() string {
+	return SchemeNamePromptPayBillPayment
+}
+
+func (promptPayBillPaymentScheme) Detect(fields map[string]string, _ []Segment) bool {
+	return isPromptPayTemplate(fields[IDMerchantInformationBOTBillPayment])
+}
+
+func (s promptPayBillPaymentScheme) Validate(fields map[string]string) error {
+	billPayment, err := s.DecodeTemplate(fields)
+	if err != nil {
+		return err
+	}
+	bp := billPayment.(*BillPayment)
+	if !slices.Contains([]string{GUIDPromptPayBillPayment, GUIDPromptPayBillPaymentCrossBorder}, bp.AID) {
+		return errors.New("invalid bill payment aid")
+	}
+	if bp.BillerID == "" {
+		return errors.New("biller id is required")
+	}
+	return nil
+}
+
+func (promptPayBillPaymentScheme) DecodeTemplate(fields map[string]string) (any, error) {
+	billPayment, err := decodeBillPayment(fields[IDMerchantInformationBOTBillPayment])
+	if err != nil {
+		return nil, err
+	}
+	return billPayment, nil
+}
+
+func (promptPayBillPaymentScheme) EncodeTemplate(template any) ([]string, error) {
+	billPayment, ok := template.(*BillPayment)
+	if !ok || billPayment == nil {
+		return nil, errors.New("bill payment template required")
+	}
+	b := &payloadBuilder{}
+	b.template(IDMerchantInformationBOTBillPayment, encodeBillPayment(billPayment))
+	field, err := b.string()
+	if err != nil {
+		return nil, err
+	}
+	return []string{field}, nil
+}
+
+// decodeCreditTransfer decodes a tag 29 template. On a malformed template it returns an
+// empty CreditTransfer along with the error, so lenient readers can keep going.
+func decodeCreditTransfer(data string) (*CreditTransfer, error) {
+	fields, segments, err := deserialize(data)
+	return &CreditTransfer{
+		AID:         fields[BOTIDCreditTransferAID],
+		MSISDN:      fields[BOTIDMerchantMSISDN],
+		NationalID:  fields[BOTIDMerchantNationalID],
+		EWalletID:   fields[BOTIDMerchantEWalletID],
+		BankAccount: fields[BOTIDMerchantBankAccount],
+		OTA:         fields[BOTIDMerchantOTA],
+		Segments:    &segments,
+	}, err
+}
+
+// encodeCreditTransfer serializes the non-empty fields of a credit transfer template.
+func encodeCreditTransfer(creditTransfer *CreditTransfer) *payloadBuilder {
+	b := &payloadBuilder{}
+	b.field(BOTIDCreditTransferAID, creditTransfer.AID)
+	for _, field := range []struct{ id, value string }{
+		{BOTIDMerchantMSISDN, creditTransfer.MSISDN},
+		{BOTIDMerchantNationalID, creditTransfer.NationalID},
+		{BOTIDMerchantEWalletID, creditTransfer.EWalletID},
+		{BOTIDMerchantBankAccount, creditTransfer.BankAccount},
+		{BOTIDMerchantOTA, creditTransfer.OTA},
+	} {
+		if field.value != "" {
+			b.field(field.id, field.value)
+		}
+	}
+	return b
+}
+
+// decodeBillPayment decodes a tag 30 template. On a malformed template it returns an
+// empty BillPayment along with the error, so lenient readers can keep going.
+func decodeBillPayment(data string) (*BillPayment, error) {
+	fields, segments, err := deserialize(data)
+	return &BillPayment{
+		AID:        fields[BOTIDBillPaymentAID],
+		BillerID:   fields[BOTIDBillPaymentBillerID],
+		Reference1: fields[BOTIDBillPaymentRef1],
+		Reference2: fields[BOTIDBillPaymentRef2],
+		Segments:   &segments,
+	}, err
+}
+
+// encodeBillPayment serializes a bill payment template. Both references are always written,
+// as PromptPay bill payment QRs carry them even when empty.
+func encodeBillPayment(billPayment *BillPayment) *payloadBuilder {
+	b := &payloadBuilder{}
+	b.field(BOTIDBillPaymentAID, billPayment.AID)
+	b.field(BOTIDBillPaymentBillerID, billPayment.BillerID)
+	b.field(BOTIDBillPaymentRef1, billPayment.Reference1)
+	b.field(BOTIDBillPaymentRef2, billPayment.Reference2)
+	return b
+}
@@ -2,17 +2,29 @@ package thaiqr
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
-	qr "github.com/skip2/go-qrcode"
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+	"golang.org/x/image/draw"
 	"image"
-	"image/draw"
-	"image/png"
-	"os"
+	"math"
 )
 
-func GenerateQR(payload string) (*[]byte, error) {
-	qrBytes, err := qr.Encode(payload, qr.Highest, 512)
+// ErrQRNotDecodable is returned when WithVerify is set and the rendered QR code does not decode back to its payload,
+// e.g. because a custom logo or low contrast colours hide too many modules.
+var ErrQRNotDecodable = errors.New("rendered qr code cannot be decoded")
 
+// GenerateQR generates a PNG QR code, rendered with the options given by WithRenderOptions.
+func GenerateQR(payload string, opts ...Option) (*[]byte, error) {
+	o := newOptions(opts)
+	ro := o.render.normalize()
+	img, err := renderQR(payload, ro, nil, o.verify)
+	if err != nil {
+		return nil, err
+	}
+
+	qrBytes, err := encodePNG(img, ro.DPI)
 	if err != nil {
 		fmt.Println("Failed to encode QR:", err)
 		return nil, err
@@ -20,8 +32,9 @@ func GenerateQR(payload string) (*[]byte, error) {
 	return &qrBytes, nil
 }
 
-func GenerateQRWithThaiQRLogo(payload string) (*[]byte, error) {
-	qrCode, err := EncodeThaiQRLogo(payload)
+// GenerateQRWithThaiQRLogo generates a PNG QR code with the Thai QR Payment logo, or the logo given by WithLogo or WithLogoFS, in its center.
+func GenerateQRWithThaiQRLogo(payload string, opts ...Option) (*[]byte, error) {
+	qrCode, err := EncodeThaiQRLogo(payload, opts...)
 	if err != nil {
 		fmt.Println("Failed to encode QR:", err)
 		return nil, err
@@ -32,48 +45,99 @@ func GenerateQRWithThaiQRLogo(payload string) (*[]byte, error) {
 	return &qrBytes, nil
 }
 
-func EncodeThaiQRLogo(content string) (*bytes.Buffer, error) {
-	var buf bytes.Buffer
-
-	file, err := os.Open("assets/thaiqr.png")
+// EncodeThaiQRLogo encodes content as a PNG QR code with a logo overlay. The bundled logo is embedded in the binary,
+// so it works regardless of the working directory. The logo is scaled to a share of the symbol the error correction
+// level can recover, see WithRenderOptions.
+func EncodeThaiQRLogo(content string, opts ...Option) (*bytes.Buffer, error) {
+	o := newOptions(opts)
+	ro := o.render.normalize()
+	logo, err := o.loadLogo()
 	if err != nil {
-		fmt.Println("Failed to open logo:", err)
 		return nil, err
 	}
-	defer func(file *os.File) {
-		_ = file.Close()
-	}(file)
 
-	logo, _, err := image.Decode(file)
+	img, err := renderQR(content, ro, logo, o.verify)
 	if err != nil {
-		fmt.Println("Failed to decode PNG with logo:", err)
 		return nil, err
 	}
 
-	code, err := qr.New(content, qr.Highest)
+	data, err := encodePNG(img, ro.DPI)
 	if err != nil {
 		return nil, err
 	}
 
-	img := code.Image(512)
-	p, ok := img.(*image.Paletted)
-	if !ok {
-		return nil, fmt.Errorf("undefined qr-code type provided: %T", img)
-	}
+	return bytes.NewBuffer(data), nil
+}
 
-	if err = png.Encode(&buf, overlayLogo(p, logo)); err != nil {
+// renderQR rasterises content, overlays logo unless it is nil and, when verify is set, checks the
+// result decodes back to content. ro must be normalized.
+func renderQR(content string, ro RenderOptions, logo image.Image, verify bool) (image.Image, error) {
+	paletted, geometry, err := renderImage(content, ro)
+	if err != nil {
 		return nil, err
 	}
+	var img image.Image = paletted
+	if logo != nil {
+		img = overlayLogo(paletted, geometry, logo, ro.ErrorCorrection)
+	}
+	if verify {
+		if err := verifyQR(img, content); err != nil {
+			return nil, err
+		}
+	}
+	return img, nil
+}
 
-	return &buf, nil
+// logoScale returns the share of the symbol width a centre logo may take at the error correction level.
+// The logo hides every module under it, so it stays well within the codewords the level can recover.
+func logoScale(level ErrorCorrectionLevel) float64 {
+	switch level {
+	case ErrorCorrectionLow:
+		return 0.1
+	case ErrorCorrectionMedium:
+		return 0.14
+	case ErrorCorrectionQuartile:
+		return 0.17
+	default:
+		return 0.2
+	}
 }
 
-// overlayLogo - blends logo to the center of the QR code.
-func overlayLogo(dst *image.Paletted, logo image.Image) *image.NRGBA {
+// overlayLogo - scales logo to the share of the symbol given by logoScale, clears the modules behind it
+// with a one module margin and draws it in the center of the QR code. geometry is where renderImage drew
+// the symbol in dst.
+func overlayLogo(dst *image.Paletted, geometry qrGeometry, logo image.Image, level ErrorCorrectionLevel) *image.NRGBA {
 	res := image.NewNRGBA(dst.Rect)
-	draw.Draw(res, res.Bounds(), dst, image.Point{0, 0}, draw.Src)
-	offsetX := dst.Bounds().Max.X/2 - logo.Bounds().Max.Y/2
-	offsetY := dst.Bounds().Max.Y/2 - logo.Bounds().Max.Y/2
-	draw.Draw(res, res.Bounds(), logo, image.Point{-offsetX, -offsetY}, draw.Over)
+	draw.Draw(res, res.Bounds(), dst, dst.Rect.Min, draw.Src)
+
+	symbol := geometry.symbol()
+	bounds := logo.Bounds()
+	if symbol.Empty() || bounds.Empty() {
+		return res
+	}
+
+	scale := float64(symbol.Dx()) * logoScale(level) / float64(max(bounds.Dx(), bounds.Dy()))
+	width := max(int(math.Round(float64(bounds.Dx())*scale)), 1)
+	height := max(int(math.Round(float64(bounds.Dy())*scale)), 1)
+	left := (symbol.Min.X + symbol.Max.X - width) / 2
+	top := (symbol.Min.Y + symbol.Max.Y - height) / 2
+	logoRect := image.Rect(left, top, left+width, top+height)
+
+	draw.Draw(res, logoRect.Inset(-geometry.moduleSize), image.NewUniform(dst.Palette[0]), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(res, logoRect, logo, bounds, draw.Over, nil)
 	return res
 }
+
+// verifyQR decodes img and checks it holds content.
+func verifyQR(img image.Image, content string) error {
+	bitmap, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		return ErrQRNotDecodable
+	}
+	hints := map[gozxing.DecodeHintType]interface{}{gozxing.DecodeHintType_TRY_HARDER: true}
+	result, err := qrcode.NewQRCodeReader().Decode(bitmap, hints)
+	if err != nil || result.GetText() != content {
+		return ErrQRNotDecodable
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package thaiqr_test
+
+import (
+	"bytes"
+	"github.com/Jdemon/thaiqr"
+	"github.com/stretchr/testify/assert"
+	"image"
+	"image/color"
+	"image/png"
+	"io/fs"
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+const logoTestPayload = "00020101021129370016A000000677010111011300669097648565802TH530376463048956"
+
+func TestGenerateQRWithThaiQRLogoOutsideRepoRoot(t *testing.T) {
+	wd, err := os.Getwd()
+	assert.Nil(t, err)
+	assert.Nil(t, os.Chdir(t.TempDir()))
+	defer func() {
+		_ = os.Chdir(wd)
+	}()
+
+	qrBytes, err := thaiqr.GenerateQRWithThaiQRLogo(logoTestPayload)
+	assert.Nil(t, err)
+
+	img, err := png.Decode(bytes.NewReader(*qrBytes))
+	assert.Nil(t, err)
+	assert.Equal(t, 512, img.Bounds().Dx())
+}
+
+func TestThaiQRLogoIsDecodedOnce(t *testing.T) {
+	first, err := thaiqr.ThaiQRLogo()
+	assert.Nil(t, err)
+	second, err := thaiqr.ThaiQRLogo()
+	assert.Nil(t, err)
+	assert.Same(t, first, second)
+}
+
+func TestEncodeThaiQRLogoWithLogo(t *testing.T) {
+	logo := image.NewNRGBA(image.Rect(0, 0, 40, 40))
+	for x := 0; x < 40; x++ {
+		for y := 0; y < 40; y++ {
+			logo.Set(x, y, color.NRGBA{R: 255, A: 255})
+		}
+	}
+
+	buf, err := thaiqr.EncodeThaiQRLogo(logoTestPayload, thaiqr.WithLogo(logo))
+	assert.Nil(t, err)
+
+	img, err := png.Decode(buf)
+	assert.Nil(t, err)
+	r, g, b, _ := img.At(256, 256).RGBA()
+	assert.Equal(t, []uint32{0xffff, 0, 0}, []uint32{r, g, b})
+}
+
+// countingFS is a comparable fs.FS counting how many times files are opened.
+type countingFS struct {
+	fsys  fstest.MapFS
+	opens int
+}
+
+func (c *countingFS) Open(name string) (fs.File, error) {
+	c.opens++
+	return c.fsys.Open(name)
+}
+
+func TestEncodeThaiQRLogoWithLogoFS(t *testing.T) {
+	var logoPNG bytes.Buffer
+	assert.Nil(t, png.Encode(&logoPNG, image.NewNRGBA(image.Rect(0, 0, 40, 40))))
+
+	fsys := &countingFS{fsys: fstest.MapFS{"logo.png": {Data: logoPNG.Bytes()}}}
+	for i := 0; i < 3; i++ {
+		_, err := thaiqr.EncodeThaiQRLogo(logoTestPayload, thaiqr.WithLogoFS(fsys, "logo.png"))
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, 1, fsys.opens)
+
+	// fstest.MapFS is a map, so it is not comparable and is read on every call
+	_, err := thaiqr.EncodeThaiQRLogo(logoTestPayload, thaiqr.WithLogoFS(fsys.fsys, "logo.png"))
+	assert.Nil(t, err)
+
+	_, err = thaiqr.EncodeThaiQRLogo(logoTestPayload, thaiqr.WithLogoFS(fsys, "missing.png"))
+	assert.Error(t, err)
+}
+
+func TestEncodeThaiQRLogoWithLogoFSCacheIsBounded(t *testing.T) {
+	var logoPNG bytes.Buffer
+	assert.Nil(t, png.Encode(&logoPNG, image.NewNRGBA(image.Rect(0, 0, 40, 40))))
+	files := fstest.MapFS{"logo.png": {Data: logoPNG.Bytes()}}
+
+	first := &countingFS{fsys: files}
+	_, err := thaiqr.EncodeThaiQRLogo(logoTestPayload, thaiqr.WithLogoFS(first, "logo.png"))
+	assert.Nil(t, err)
+	// decoding many other file systems evicts the first logo
+	for i := 0; i < 32; i++ {
+		_, err = thaiqr.EncodeThaiQRLogo(logoTestPayload, thaiqr.WithLogoFS(&countingFS{fsys: files}, "logo.png"))
+		assert.Nil(t, err)
+	}
+	_, err = thaiqr.EncodeThaiQRLogo(logoTestPayload, thaiqr.WithLogoFS(first, "logo.png"))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, first.opens)
+}
+
+func TestEncodeThaiQRLogoScalesAndCentresLogo(t *testing.T) {
+	// a wide logo, so centring on the logo height would shift it sideways
+	logo := image.NewNRGBA(image.Rect(0, 0, 300, 60))
+	for x := 0; x < 300; x++ {
+		for y := 0; y < 60; y++ {
+			logo.Set(x, y, color.NRGBA{R: 255, A: 255})
+		}
+	}
+
+	for _, level := range []thaiqr.ErrorCorrectionLevel{
+		thaiqr.ErrorCorrectionLow,
+		thaiqr.ErrorCorrectionMedium,
+		thaiqr.ErrorCorrectionQuartile,
+		thaiqr.ErrorCorrectionHigh,
+	} {
+		buf, err := thaiqr.EncodeThaiQRLogo(logoTestPayload, thaiqr.WithLogo(logo), thaiqr.WithVerify(),
+			thaiqr.WithRenderOptions(thaiqr.RenderOptions{ErrorCorrection: level}))
+		assert.Nil(t, err, level)
+
+		img, err := png.Decode(buf)
+		assert.Nil(t, err)
+		red := image.Rectangle{}
+		for y := 0; y < img.Bounds().Dy(); y++ {
+			for x := 0; x < img.Bounds().Dx(); x++ {
+				if r, g, _, _ := img.At(x, y).RGBA(); r > 0xf000 && g < 0x1000 {
+					red = red.Union(image.Rect(x, y, x+1, y+1))
+				}
+			}
+		}
+		// scaled down from 300px to at most a fifth of the symbol, centred on both axes
+		assert.LessOrEqual(t, red.Dx(), 512/5, level)
+		assert.InDelta(t, 256, (red.Min.X+red.Max.X)/2, 3, level)
+		assert.InDelta(t, 256, (red.Min.Y+red.Max.Y)/2, 3, level)
+		// the modules right around the logo are cleared
+		assert.Equal(t, color.Gray16{Y: 0xffff}, color.Gray16Model.Convert(img.At(red.Min.X-2, red.Min.Y-2)), level)
+	}
+}
+
+func TestEncodeThaiQRLogoVerify(t *testing.T) {
+	_, err := thaiqr.EncodeThaiQRLogo(logoTestPayload, thaiqr.WithVerify())
+	assert.Nil(t, err)
+
+	_, err = thaiqr.GenerateQR(logoTestPayload, thaiqr.WithVerify(), thaiqr.WithRenderOptions(thaiqr.RenderOptions{
+		Foreground: color.White,
+	}))
+	assert.ErrorIs(t, err, thaiqr.ErrQRNotDecodable)
+}
@@ -0,0 +1,239 @@
+package thaiqr
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// QRISIDGUID QRIS Merchant Account Information Tags 26-45 and National Merchant Information Tag 51
+	QRISIDGUID             = "00"
+	QRISIDMerchantPAN      = "01"
+	QRISIDMerchantID       = "02"
+	QRISIDMerchantCriteria = "03"
+
+	QRISIDTag62BillNumber = "01"
+	QRISIDTag62TerminalID = "07"
+
+	IDMerchantInformationQRIS = "51"
+	TransactionCurrencyIDR    = "360"
+	CountryCodeID             = "ID"
+
+	QRISMerchantCriteriaMicro   = "UMI"
+	QRISMerchantCriteriaSmall   = "UKE"
+	QRISMerchantCriteriaMedium  = "UME"
+	QRISMerchantCriteriaLarge   = "UBE"
+	QRISMerchantCriteriaRegular = "URE"
+)
+
+// QRISAcquirer is an acquirer specific merchant account template (tags 26-45) of a QRIS payload.
+type QRISAcquirer struct {
+	ID               string `json:"id"`
+	Domain           string `json:"domain"`
+	MerchantPAN      string `json:"merchantPan"`
+	MerchantID       string `json:"merchantId,omitempty"`
+	MerchantCriteria string `json:"merchantCriteria,omitempty"`
+}
+
+type QRISQRCmd struct {
+	NMID                 string         `json:"nmid"`
+	MerchantCriteria     string         `json:"merchantCriteria"`
+	Acquirers            []QRISAcquirer `json:"acquirers"`
+	Amount               string         `json:"amount"`
+	MerchantCategoryCode string         `json:"merchantCategoryCode"`
+	MerchantName         string         `json:"merchantName"`
+	MerchantCity         string         `json:"merchantCity"`
+	PostalCode           string         `json:"postalCode"`
+	BillNumber           string         `json:"billNumber"`
+	TerminalID           string         `json:"terminalId"`
+}
+
+type QRISQRResults struct {
+	PayloadFormatIndicator  string         `json:"payloadFormatIndicator"`
+	PointOfInitiationMethod string         `json:"pointOfInitiationMethod"`
+	Acquirers               []QRISAcquirer `json:"acquirers"`
+	NMID                    string         `json:"nmid"`
+	MerchantCriteria        string         `json:"merchantCriteria"`
+	MerchantCategoryCode    string         `json:"merchantCategoryCode,omitempty"`
+	TransactionCurrency     string         `json:"transactionCurrency"`
+	TransactionCurrencyCode string         `json:"transactionCurrencyCode"`
+	TransactionAmount       string         `json:"transactionAmount,omitempty"`
+	CountryCode             string         `json:"countryCode"`
+	MerchantName            string         `json:"merchantName,omitempty"`
+	MerchantCity            string         `json:"merchantCity,omitempty"`
+	PostalCode              string         `json:"postalCode,omitempty"`
+	BillNumber              string         `json:"billNumber,omitempty"`
+	TerminalID              string         `json:"terminalId,omitempty"`
+	CRC                     string         `json:"crc"`
+	Segments                *[]Segment     `json:"segments,omitempty"`
+}
+
+// QRISQR represents an Indonesia QRIS QR code generator.
+type QRISQR struct{}
+
+// NewQRISQR returns a new QRISQR instance.
+func NewQRISQR() *QRISQR {
+	return &QRISQR{}
+}
+
+// GeneratePayload generates a QRIS QR code payload. IDR has no minor units, so the amount must be a whole number.
+func (qr *QRISQR) GeneratePayload(cmd QRISQRCmd) (string, error) {
+	if strings.TrimSpace(cmd.NMID) == "" {
+		return "", errors.New("nmid is required")
+	}
+	if !isQRISMerchantCriteria(cmd.MerchantCriteria) {
+		return "", errors.New("invalid merchant criteria")
+	}
+	if len(cmd.Acquirers) == 0 {
+		return "", errors.New("at least one acquirer is required")
+	}
+
+	amount := strings.TrimSpace(cmd.Amount)
+	data := &payloadBuilder{}
+	data.field(IDPayloadFormat, PayloadFormatEMVQRCPSMerchantPresentedMode)
+	data.field(IDPOIMethod, ifThenElse(amount != "", POIMethodDynamic, POIMethodStatic).(string))
+
+	used := make(map[string]bool, len(cmd.Acquirers))
+	for i, acquirer := range cmd.Acquirers {
+		id := ifThenElse(acquirer.ID != "", acquirer.ID, fmt.Sprintf("%02d", 26+i)).(string)
+		if !isQRISAcquirerID(id) || used[id] {
+			return "", fmt.Errorf("invalid acquirer template %s", id)
+		}
+		used[id] = true
+		if acquirer.Domain == "" || acquirer.MerchantPAN == "" {
+			return "", errors.New("acquirer domain and merchant pan are required")
+		}
+
+		acquirerData := &payloadBuilder{}
+		acquirerData.field(QRISIDGUID, acquirer.Domain)
+		acquirerData.field(QRISIDMerchantPAN, sanitizeTarget(acquirer.MerchantPAN))
+		if acquirer.MerchantID != "" {
+			acquirerData.field(QRISIDMerchantID, acquirer.MerchantID)
+		}
+		acquirerData.field(QRISIDMerchantCriteria,
+			ifThenElse(acquirer.MerchantCriteria != "", acquirer.MerchantCriteria, cmd.MerchantCriteria).(string))
+		data.template(id, acquirerData)
+	}
+
+	merchantInfoData := &payloadBuilder{}
+	merchantInfoData.field(QRISIDGUID, GUIDQRIS)
+	merchantInfoData.field(QRISIDMerchantID, cmd.NMID)
+	merchantInfoData.field(QRISIDMerchantCriteria, cmd.MerchantCriteria)
+	data.template(IDMerchantInformationQRIS, merchantInfoData)
+	data.field(IDMerchantCategoryCode, ifThenElse(cmd.MerchantCategoryCode != "", cmd.MerchantCategoryCode, "0000").(string))
+	data.field(IDTransactionCurrency, TransactionCurrencyIDR)
+	if amount != "" {
+		amountFormat, err := formatAmountWithExponent(amount, 0)
+		if err != nil {
+			return "", err
+		}
+		data.field(IDTransactionAmount, amountFormat)
+	}
+	data.field(IDCountryCode, CountryCodeID)
+	data.field(IDMerchantName, ifThenElse(cmd.MerchantName != "", cmd.MerchantName, "NA").(string))
+	data.field(IDMerchantCity, ifThenElse(cmd.MerchantCity != "", cmd.MerchantCity, "JAKARTA").(string))
+	if strings.TrimSpace(cmd.PostalCode) != "" {
+		data.field(IDPostalCode, cmd.PostalCode)
+	}
+
+	additionalData := &payloadBuilder{}
+	if strings.TrimSpace(cmd.BillNumber) != "" {
+		additionalData.field(QRISIDTag62BillNumber, cmd.BillNumber)
+	}
+	if strings.TrimSpace(cmd.TerminalID) != "" {
+		additionalData.field(QRISIDTag62TerminalID, cmd.TerminalID)
+	}
+	if !additionalData.empty() {
+		data.template(IDAdditionalFields, additionalData)
+	}
+
+	return data.withChecksum(IDCRC)
+}
+
+// Reader decodes a QRIS QR code payload.
+func (qr *QRISQR) Reader(data string) (*QRISQRResults, error) {
+	qrFields, qrSegments, err := deserializeMerchantPresented(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Acquirer templates (26-50) may carry the QRIS GUID too, so the national template is looked up by its ID.
+	nationalFields, _, err := deserialize(qrFields[IDMerchantInformationQRIS])
+	if err != nil || !strings.EqualFold(nationalFields[QRISIDGUID], GUIDQRIS) {
+		return nil, errors.New("qris merchant information not found")
+	}
+
+	transactionCurrency := qrFields[IDTransactionCurrency]
+	if transactionCurrency != TransactionCurrencyIDR {
+		return nil, errors.New("invalid currency")
+	}
+	transactionAmount := qrFields[IDTransactionAmount]
+	if transactionAmount != "" {
+		if _, err := formatAmountWithExponent(transactionAmount, 0); err != nil {
+			return nil, err
+		}
+	}
+	countryCode := qrFields[IDCountryCode]
+	if len(countryCode) != 2 {
+		return nil, invalidFormat()
+	}
+
+	acquirers := make([]QRISAcquirer, 0)
+	for _, segment := range qrSegments {
+		if !isQRISAcquirerID(segment.ID) {
+			continue
+		}
+		acquirerFields, _, err := deserialize(segment.Value)
+		if err != nil {
+			return nil, err
+		}
+		acquirers = append(acquirers, QRISAcquirer{
+			ID:               segment.ID,
+			Domain:           acquirerFields[QRISIDGUID],
+			MerchantPAN:      acquirerFields[QRISIDMerchantPAN],
+			MerchantID:       acquirerFields[QRISIDMerchantID],
+			MerchantCriteria: acquirerFields[QRISIDMerchantCriteria],
+		})
+	}
+
+	additionalFields, _, _ := deserialize(qrFields[IDAdditionalFields])
+
+	return &QRISQRResults{
+		PayloadFormatIndicator:  qrFields[IDPayloadFormat],
+		PointOfInitiationMethod: qrFields[IDPOIMethod],
+		Acquirers:               acquirers,
+		NMID:                    nationalFields[QRISIDMerchantID],
+		MerchantCriteria:        nationalFields[QRISIDMerchantCriteria],
+		MerchantCategoryCode:    qrFields[IDMerchantCategoryCode],
+		TransactionCurrency:     transactionCurrency,
+		TransactionCurrencyCode: GetCurrencyCode(transactionCurrency),
+		TransactionAmount:       transactionAmount,
+		CountryCode:             countryCode,
+		MerchantName:            qrFields[IDMerchantName],
+		MerchantCity:            qrFields[IDMerchantCity],
+		PostalCode:              qrFields[IDPostalCode],
+		BillNumber:              additionalFields[QRISIDTag62BillNumber],
+		TerminalID:              additionalFields[QRISIDTag62TerminalID],
+		CRC:                     qrFields[IDCRC],
+		Segments:                &qrSegments,
+	}, nil
+}
+
+// isQRISAcquirerID reports whether id is one of the acquirer specific templates (26-45).
+func isQRISAcquirerID(id string) bool {
+	n, err := strconv.Atoi(id)
+	return err == nil && len(id) == 2 && n >= 26 && n <= 45
+}
+
+func isQRISMerchantCriteria(criteria string) bool {
+	return slices.Contains([]string{
+		QRISMerchantCriteriaMicro,
+		QRISMerchantCriteriaSmall,
+		QRISMerchantCriteriaMedium,
+		QRISMerchantCriteriaLarge,
+		QRISMerchantCriteriaRegular,
+	}, criteria)
+}
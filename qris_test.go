@@ -0,0 +1,95 @@
+package thaiqr_test
+
+import (
+	"github.com/Jdemon/thaiqr"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGenerateQRISMustValid(t *testing.T) {
+	qr := thaiqr.NewQRISQR()
+	cmd := thaiqr.QRISQRCmd{
+		NMID:             "ID1020021181745",
+		MerchantCriteria: thaiqr.QRISMerchantCriteriaMicro,
+		Acquirers: []thaiqr.QRISAcquirer{
+			{Domain: "ID.CO.BANKMANDIRI.WWW", MerchantPAN: "936000080000000001", MerchantID: "000000000000001"},
+		},
+		Amount:       "25000",
+		MerchantName: "WARUNG BALI",
+		MerchantCity: "DENPASAR",
+		PostalCode:   "80361",
+	}
+	actualPayload, err := qr.GeneratePayload(cmd)
+	assert.Nil(t, err)
+	assert.Equal(t, "00020101021226730021ID.CO.BANKMANDIRI.WWW01189360000800000000010215000000000000001"+
+		"0303UMI51440014ID.CO.QRIS.WWW0215ID10200211817450303UMI5204000053033605405250005802ID"+
+		"5911WARUNG BALI6008DENPASAR6105803616304760E", actualPayload)
+
+	result, err := qr.Reader(actualPayload)
+	assert.Nil(t, err)
+	assert.Equal(t, cmd.NMID, result.NMID)
+	assert.Equal(t, thaiqr.QRISMerchantCriteriaMicro, result.MerchantCriteria)
+	assert.Len(t, result.Acquirers, 1)
+	assert.Equal(t, "26", result.Acquirers[0].ID)
+	assert.Equal(t, "ID.CO.BANKMANDIRI.WWW", result.Acquirers[0].Domain)
+	assert.Equal(t, "936000080000000001", result.Acquirers[0].MerchantPAN)
+	assert.Equal(t, "25000", result.TransactionAmount)
+	assert.Equal(t, "IDR", result.TransactionCurrencyCode)
+	assert.Equal(t, thaiqr.CountryCodeID, result.CountryCode)
+	assert.Equal(t, cmd.PostalCode, result.PostalCode)
+
+	parsed, err := thaiqr.Parse(actualPayload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.QRTypeQRIS, parsed.Type)
+	assert.Equal(t, cmd.NMID, parsed.QRIS.NMID)
+}
+
+func TestReadQRISAcquirerWithQRISGUID(t *testing.T) {
+	qr := thaiqr.NewQRISQR()
+	payload, err := qr.GeneratePayload(thaiqr.QRISQRCmd{
+		NMID:             "ID1020021181745",
+		MerchantCriteria: thaiqr.QRISMerchantCriteriaMicro,
+		Acquirers:        []thaiqr.QRISAcquirer{{Domain: thaiqr.GUIDQRIS, MerchantPAN: "936000080000000001"}},
+	})
+	assert.Nil(t, err)
+	// the acquirer template 26 precedes the national template 51 and carries the same GUID
+	assert.Regexp(t, `^00020101021126\d\d0014ID\.CO\.QRIS\.WWW`, payload)
+
+	result, err := qr.Reader(payload)
+	assert.Nil(t, err)
+	assert.Equal(t, "ID1020021181745", result.NMID)
+	assert.Equal(t, thaiqr.GUIDQRIS, result.Acquirers[0].Domain)
+}
+
+func TestGenerateQRISRejectsFractionalAmount(t *testing.T) {
+	_, err := thaiqr.NewQRISQR().GeneratePayload(thaiqr.QRISQRCmd{
+		NMID:             "ID1020021181745",
+		MerchantCriteria: thaiqr.QRISMerchantCriteriaMicro,
+		Acquirers:        []thaiqr.QRISAcquirer{{Domain: "ID.CO.BANKMANDIRI.WWW", MerchantPAN: "936000080000000001"}},
+		Amount:           "100.50",
+	})
+	assert.EqualError(t, err, "invalid amount")
+}
+
+func TestGenerateQRISInvalidCmd(t *testing.T) {
+	qr := thaiqr.NewQRISQR()
+	_, err := qr.GeneratePayload(thaiqr.QRISQRCmd{NMID: "ID1020021181745", MerchantCriteria: "XXX"})
+	assert.Error(t, err)
+
+	_, err = qr.GeneratePayload(thaiqr.QRISQRCmd{NMID: "ID1020021181745", MerchantCriteria: thaiqr.QRISMerchantCriteriaMicro})
+	assert.Error(t, err)
+
+	_, err = qr.GeneratePayload(thaiqr.QRISQRCmd{
+		NMID:             "ID1020021181745",
+		MerchantCriteria: thaiqr.QRISMerchantCriteriaMicro,
+		Acquirers:        []thaiqr.QRISAcquirer{{ID: "46", Domain: "ID.CO.BANKMANDIRI.WWW", MerchantPAN: "936000080000000001"}},
+	})
+	assert.Error(t, err)
+}
+
+func TestReadQRISShortPayload(t *testing.T) {
+	for _, data := range []string{"", "ab", "abcd", "000201"} {
+		_, err := thaiqr.NewQRISQR().Reader(data)
+		assert.Error(t, err, data)
+	}
+}
@@ -0,0 +1,198 @@
+package thaiqr
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+const (
+	// QRPhIDGUID QR Ph P2P Merchant Account Information Tag 27 and P2M Tag 28
+	QRPhIDGUID          = "00"
+	QRPhIDAcquirerID    = "01"
+	QRPhIDMerchantID    = "03"
+	QRPhIDMobileNumber  = "03"
+	QRPhIDAccountNumber = "04"
+
+	QRPhIDTag62ReferenceLabel       = "05"
+	QRPhIDTag62PurposeOfTransaction = "08"
+
+	IDMerchantInformationQRPhP2P = "27"
+	IDMerchantInformationQRPhP2M = "28"
+	GUIDQRPhP2P                  = "com.p2pqrpay"
+	GUIDQRPhP2M                  = "ph.ppmi.p2m"
+	TransactionCurrencyPHP       = "608"
+	CountryCodePH                = "PH"
+
+	QRPhTypeP2P = "P2P"
+	QRPhTypeP2M = "P2M"
+)
+
+type QRPhCmd struct {
+	Type                 string `json:"type"`
+	AcquirerID           string `json:"acquirerId"`
+	MerchantID           string `json:"merchantId"`
+	MobileNumber         string `json:"mobileNumber"`
+	AccountNumber        string `json:"accountNumber"`
+	Amount               string `json:"amount"`
+	MerchantCategoryCode string `json:"merchantCategoryCode"`
+	MerchantName         string `json:"merchantName"`
+	MerchantCity         string `json:"merchantCity"`
+	PostalCode           string `json:"postalCode"`
+	ReferenceLabel       string `json:"referenceLabel"`
+	PurposeOfTransaction string `json:"purposeOfTransaction"`
+}
+
+type QRPhResults struct {
+	PayloadFormatIndicator  string     `json:"payloadFormatIndicator"`
+	PointOfInitiationMethod string     `json:"pointOfInitiationMethod"`
+	Type                    string     `json:"type"`
+	GUID                    string     `json:"guid"`
+	AcquirerID              string     `json:"acquirerId"`
+	MerchantID              string     `json:"merchantId,omitempty"`
+	MobileNumber            string     `json:"mobileNumber,omitempty"`
+	AccountNumber           string     `json:"accountNumber,omitempty"`
+	MerchantCategoryCode    string     `json:"merchantCategoryCode,omitempty"`
+	TransactionCurrency     string     `json:"transactionCurrency"`
+	TransactionCurrencyCode string     `json:"transactionCurrencyCode"`
+	TransactionAmount       string     `json:"transactionAmount,omitempty"`
+	CountryCode             string     `json:"countryCode"`
+	MerchantName            string     `json:"merchantName,omitempty"`
+	MerchantCity            string     `json:"merchantCity,omitempty"`
+	PostalCode              string     `json:"postalCode,omitempty"`
+	ReferenceLabel          string     `json:"referenceLabel,omitempty"`
+	PurposeOfTransaction    string     `json:"purposeOfTransaction,omitempty"`
+	CRC                     string     `json:"crc"`
+	Segments                *[]Segment `json:"segments,omitempty"`
+}
+
+// QRPh represents a Philippines QR Ph QR code generator.
+type QRPh struct{}
+
+// NewQRPh returns a new QRPh instance.
+func NewQRPh() *QRPh {
+	return &QRPh{}
+}
+
+var bicPattern = regexp.MustCompile(`^[A-Z]{6}[A-Z0-9]{2}([A-Z0-9]{3})?$`)
+
+// GeneratePayload generates a QR Ph payload for a person to person or person to merchant transfer.
+func (qr *QRPh) GeneratePayload(cmd QRPhCmd) (string, error) {
+	qrType := strings.ToUpper(ifThenElse(cmd.Type != "", cmd.Type, QRPhTypeP2P).(string))
+	if !bicPattern.MatchString(cmd.AcquirerID) {
+		return "", errors.New("invalid acquirer id")
+	}
+
+	var accountTag string
+	accountData := &payloadBuilder{}
+	switch qrType {
+	case QRPhTypeP2P:
+		if cmd.AccountNumber == "" && cmd.MobileNumber == "" {
+			return "", errors.New("account number or mobile number is required")
+		}
+		accountTag = IDMerchantInformationQRPhP2P
+		accountData.field(QRPhIDGUID, GUIDQRPhP2P)
+		accountData.field(QRPhIDAcquirerID, cmd.AcquirerID)
+		if cmd.MobileNumber != "" {
+			accountData.field(QRPhIDMobileNumber, cmd.MobileNumber)
+		}
+		if cmd.AccountNumber != "" {
+			accountData.field(QRPhIDAccountNumber, cmd.AccountNumber)
+		}
+	case QRPhTypeP2M:
+		if cmd.MerchantID == "" || strings.TrimSpace(cmd.MerchantName) == "" {
+			return "", errors.New("merchant id and merchant name are required")
+		}
+		accountTag = IDMerchantInformationQRPhP2M
+		accountData.field(QRPhIDGUID, GUIDQRPhP2M)
+		accountData.field(QRPhIDAcquirerID, cmd.AcquirerID)
+		accountData.field(QRPhIDMerchantID, cmd.MerchantID)
+	default:
+		return "", errors.New("invalid qr ph type")
+	}
+
+	amount := strings.TrimSpace(cmd.Amount)
+	data := &payloadBuilder{}
+	data.field(IDPayloadFormat, PayloadFormatEMVQRCPSMerchantPresentedMode)
+	data.field(IDPOIMethod, ifThenElse(amount != "", POIMethodDynamic, POIMethodStatic).(string))
+	data.template(accountTag, accountData)
+	data.field(IDMerchantCategoryCode, ifThenElse(cmd.MerchantCategoryCode != "", cmd.MerchantCategoryCode, "6016").(string))
+	data.field(IDTransactionCurrency, TransactionCurrencyPHP)
+	if amount != "" {
+		amountFormat, err := formatAmountWithExponent(amount, 2)
+		if err != nil {
+			return "", err
+		}
+		data.field(IDTransactionAmount, amountFormat)
+	}
+	data.field(IDCountryCode, CountryCodePH)
+	data.field(IDMerchantName, ifThenElse(cmd.MerchantName != "", cmd.MerchantName, "NA").(string))
+	data.field(IDMerchantCity, ifThenElse(cmd.MerchantCity != "", cmd.MerchantCity, "MANILA").(string))
+	if strings.TrimSpace(cmd.PostalCode) != "" {
+		data.field(IDPostalCode, cmd.PostalCode)
+	}
+
+	additionalData := &payloadBuilder{}
+	if strings.TrimSpace(cmd.ReferenceLabel) != "" {
+		additionalData.field(QRPhIDTag62ReferenceLabel, cmd.ReferenceLabel)
+	}
+	if strings.TrimSpace(cmd.PurposeOfTransaction) != "" {
+		additionalData.field(QRPhIDTag62PurposeOfTransaction, cmd.PurposeOfTransaction)
+	}
+	if !additionalData.empty() {
+		data.template(IDAdditionalFields, additionalData)
+	}
+
+	return data.withChecksum(IDCRC)
+}
+
+// Reader decodes a QR Ph payload.
+func (qr *QRPh) Reader(data string) (*QRPhResults, error) {
+	qrFields, qrSegments, err := deserializeMerchantPresented(data)
+	if err != nil {
+		return nil, err
+	}
+
+	results := &QRPhResults{
+		PayloadFormatIndicator:  qrFields[IDPayloadFormat],
+		PointOfInitiationMethod: qrFields[IDPOIMethod],
+		MerchantCategoryCode:    qrFields[IDMerchantCategoryCode],
+		TransactionCurrency:     qrFields[IDTransactionCurrency],
+		TransactionCurrencyCode: GetCurrencyCode(qrFields[IDTransactionCurrency]),
+		TransactionAmount:       qrFields[IDTransactionAmount],
+		CountryCode:             qrFields[IDCountryCode],
+		MerchantName:            qrFields[IDMerchantName],
+		MerchantCity:            qrFields[IDMerchantCity],
+		PostalCode:              qrFields[IDPostalCode],
+		CRC:                     qrFields[IDCRC],
+		Segments:                &qrSegments,
+	}
+
+	if _, accountFields, _, ok := findMerchantAccount(qrSegments, GUIDQRPhP2M); ok {
+		results.Type = QRPhTypeP2M
+		results.GUID = accountFields[QRPhIDGUID]
+		results.AcquirerID = accountFields[QRPhIDAcquirerID]
+		results.MerchantID = accountFields[QRPhIDMerchantID]
+	} else if _, accountFields, _, ok := findMerchantAccount(qrSegments, GUIDQRPhP2P); ok {
+		results.Type = QRPhTypeP2P
+		results.GUID = accountFields[QRPhIDGUID]
+		results.AcquirerID = accountFields[QRPhIDAcquirerID]
+		results.MobileNumber = accountFields[QRPhIDMobileNumber]
+		results.AccountNumber = accountFields[QRPhIDAccountNumber]
+	} else {
+		return nil, errors.New("qr ph merchant account not found")
+	}
+
+	if results.TransactionCurrency != TransactionCurrencyPHP {
+		return nil, errors.New("invalid currency")
+	}
+	if len(results.CountryCode) != 2 {
+		return nil, invalidFormat()
+	}
+
+	additionalFields, _, _ := deserialize(qrFields[IDAdditionalFields])
+	results.ReferenceLabel = additionalFields[QRPhIDTag62ReferenceLabel]
+	results.PurposeOfTransaction = additionalFields[QRPhIDTag62PurposeOfTransaction]
+
+	return results, nil
+}
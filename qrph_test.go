@@ -0,0 +1,99 @@
+package thaiqr_test
+
+import (
+	"github.com/Jdemon/thaiqr"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGenerateQRPhP2PMustValid(t *testing.T) {
+	qr := thaiqr.NewQRPh()
+	cmd := thaiqr.QRPhCmd{
+		AcquirerID:    "BNORPHMMXXX",
+		MobileNumber:  "09171234567",
+		AccountNumber: "1234567890",
+		Amount:        "250",
+		MerchantName:  "JUAN DELA CRUZ",
+	}
+	actualPayload, err := qr.GeneratePayload(cmd)
+	assert.Nil(t, err)
+	assert.Equal(t, "00020101021227600012com.p2pqrpay0111BNORPHMMXXX03110917123456704101234567890"+
+		"5204601653036085406250.005802PH5914JUAN DELA CRUZ6006MANILA63040577", actualPayload)
+
+	result, err := qr.Reader(actualPayload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.QRPhTypeP2P, result.Type)
+	assert.Equal(t, thaiqr.GUIDQRPhP2P, result.GUID)
+	assert.Equal(t, cmd.AcquirerID, result.AcquirerID)
+	assert.Equal(t, cmd.MobileNumber, result.MobileNumber)
+	assert.Equal(t, cmd.AccountNumber, result.AccountNumber)
+	assert.Equal(t, "PHP", result.TransactionCurrencyCode)
+	assert.Equal(t, "250.00", result.TransactionAmount)
+	assert.Equal(t, thaiqr.CountryCodePH, result.CountryCode)
+
+	parsed, err := thaiqr.Parse(actualPayload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.QRTypeQRPh, parsed.Type)
+}
+
+func TestGenerateQRPhP2MMustValid(t *testing.T) {
+	qr := thaiqr.NewQRPh()
+	cmd := thaiqr.QRPhCmd{
+		Type:                 thaiqr.QRPhTypeP2M,
+		AcquirerID:           "BNORPHMMXXX",
+		MerchantID:           "MID0001",
+		MerchantCategoryCode: "5411",
+		MerchantName:         "SARI SARI STORE",
+		MerchantCity:         "QUEZON CITY",
+		ReferenceLabel:       "REF123",
+	}
+	actualPayload, err := qr.GeneratePayload(cmd)
+	assert.Nil(t, err)
+	assert.Equal(t, "00020101021128410011ph.ppmi.p2m0111BNORPHMMXXX0307MID00015204541153036085802PH"+
+		"5915SARI SARI STORE6011QUEZON CITY62100506REF1236304A9A7", actualPayload)
+
+	result, err := qr.Reader(actualPayload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.QRPhTypeP2M, result.Type)
+	assert.Equal(t, cmd.MerchantID, result.MerchantID)
+	assert.Equal(t, cmd.MerchantName, result.MerchantName)
+	assert.Equal(t, cmd.ReferenceLabel, result.ReferenceLabel)
+	assert.Empty(t, result.TransactionAmount)
+
+	parsed, err := thaiqr.Parse(actualPayload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.QRTypeQRPh, parsed.Type)
+	assert.Equal(t, cmd.MerchantID, parsed.QRPh.MerchantID)
+}
+
+func TestGenerateQRPhInvalid(t *testing.T) {
+	qr := thaiqr.NewQRPh()
+	_, err := qr.GeneratePayload(thaiqr.QRPhCmd{AcquirerID: "BNOR", MobileNumber: "09171234567"})
+	assert.Error(t, err)
+
+	_, err = qr.GeneratePayload(thaiqr.QRPhCmd{AcquirerID: "BNORPHMMXXX"})
+	assert.Error(t, err)
+
+	_, err = qr.GeneratePayload(thaiqr.QRPhCmd{Type: thaiqr.QRPhTypeP2M, AcquirerID: "BNORPHMMXXX", MerchantID: "MID0001"})
+	assert.Error(t, err)
+
+	_, err = qr.GeneratePayload(thaiqr.QRPhCmd{Type: "P2X", AcquirerID: "BNORPHMMXXX", MobileNumber: "09171234567"})
+	assert.Error(t, err)
+
+	_, err = qr.GeneratePayload(thaiqr.QRPhCmd{AcquirerID: "BNORPHMMXXX", MobileNumber: "09171234567", Amount: "-5"})
+	assert.EqualError(t, err, "invalid amount")
+}
+
+func TestGenerateQRPhLargeAmount(t *testing.T) {
+	qr := thaiqr.NewQRPh()
+	payload, err := qr.GeneratePayload(thaiqr.QRPhCmd{AcquirerID: "BNORPHMMXXX", MobileNumber: "09171234567", Amount: "1234567.89"})
+	assert.Nil(t, err)
+	assert.Contains(t, payload, "54101234567.89")
+}
+
+func TestReadQRPhShortPayload(t *testing.T) {
+	for _, data := range []string{"", "ab", "abcd", "000201"} {
+		_, err := thaiqr.NewQRPh().Reader(data)
+		assert.Error(t, err, data)
+	}
+}
@@ -0,0 +1,121 @@
+package thaiqr
+
+import (
+	"bytes"
+	"errors"
+	"github.com/makiuchi-d/gozxing"
+	multiqrcode "github.com/makiuchi-d/gozxing/multi/qrcode"
+	"github.com/makiuchi-d/gozxing/qrcode"
+	"golang.org/x/image/draw"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+)
+
+// ErrNoQRCode is returned by ReadImage when the image holds no readable QR code.
+var ErrNoQRCode = errors.New("no qr code found")
+
+// scanSide is the longest side large photos are scaled down to when they yield no QR code at full size.
+const scanSide = 1600
+
+// ImageQR is a QR code found in an image.
+type ImageQR struct {
+	// Payload is the raw text of the QR code.
+	Payload string `json:"payload"`
+	// Parsed is the payload decoded by Parse. It is only set when Err is nil.
+	Parsed Parsed `json:"parsed"`
+	// Err is the error of Parse, e.g. for a QR code that is not a payment or slip payload.
+	Err error `json:"-"`
+	// Points are the finder pattern centres in image coordinates.
+	Points []image.Point `json:"points"`
+}
+
+// ReadImageBytes decodes a PNG or JPEG image and reads the QR codes in it, see ReadImage.
+func ReadImageBytes(data []byte) ([]ImageQR, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return ReadImage(img)
+}
+
+// ReadImage locates and decodes every QR code in img, e.g. a screenshot of a transfer slip or a photo of a
+// merchant sticker, whatever its rotation or perspective, and passes each payload to Parse. A QR code
+// whose payload Parse rejects is still returned, with Err set, so one unrelated code does not hide the
+// others. Codes are returned once each, in the order they are found.
+func ReadImage(img image.Image) ([]ImageQR, error) {
+	results, err := scanImage(img)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make([]ImageQR, 0, len(results))
+	seen := make(map[string]bool)
+	for _, result := range results {
+		if seen[result.text] {
+			continue
+		}
+		seen[result.text] = true
+
+		qr := ImageQR{Payload: result.text, Points: result.points}
+		qr.Parsed, qr.Err = Parse(result.text)
+		found = append(found, qr)
+	}
+	return found, nil
+}
+
+type scanResult struct {
+	text   string
+	points []image.Point
+}
+
+// scanImage decodes the QR codes in img, retrying on a scaled down copy of large photos.
+func scanImage(img image.Image) ([]scanResult, error) {
+	bounds := img.Bounds()
+	results := decodeQRCodes(img, func(x, y float64) image.Point {
+		return image.Pt(bounds.Min.X+int(math.Round(x)), bounds.Min.Y+int(math.Round(y)))
+	})
+	if longest := max(bounds.Dx(), bounds.Dy()); len(results) == 0 && longest > scanSide {
+		scale := float64(scanSide) / float64(longest)
+		scaled := image.NewGray(image.Rect(0, 0, int(float64(bounds.Dx())*scale), int(float64(bounds.Dy())*scale)))
+		draw.ApproxBiLinear.Scale(scaled, scaled.Bounds(), img, bounds, draw.Src, nil)
+		results = decodeQRCodes(scaled, func(x, y float64) image.Point {
+			return image.Pt(bounds.Min.X+int(math.Round(x/scale)), bounds.Min.Y+int(math.Round(y/scale)))
+		})
+	}
+	if len(results) == 0 {
+		return nil, ErrNoQRCode
+	}
+	return results, nil
+}
+
+// decodeQRCodes runs the multi QR code reader, falling back to the single code reader which copes
+// better with a code filling the whole image. toImage maps the points found, relative to the top left
+// corner of img, back to the image given to ReadImage.
+func decodeQRCodes(img image.Image, toImage func(x, y float64) image.Point) []scanResult {
+	bitmap, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		return nil
+	}
+	hints := map[gozxing.DecodeHintType]interface{}{gozxing.DecodeHintType_TRY_HARDER: true}
+
+	results, err := multiqrcode.NewQRCodeMultiReader().DecodeMultiple(bitmap, hints)
+	if err != nil || len(results) == 0 {
+		result, err := qrcode.NewQRCodeReader().Decode(bitmap, hints)
+		if err != nil {
+			return nil
+		}
+		results = []*gozxing.Result{result}
+	}
+
+	scanned := make([]scanResult, 0, len(results))
+	for _, result := range results {
+		points := make([]image.Point, 0, len(result.GetResultPoints()))
+		for _, p := range result.GetResultPoints() {
+			points = append(points, toImage(p.GetX(), p.GetY()))
+		}
+		scanned = append(scanned, scanResult{text: result.GetText(), points: points})
+	}
+	return scanned
+}
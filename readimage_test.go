@@ -0,0 +1,123 @@
+package thaiqr_test
+
+import (
+	"bytes"
+	"github.com/Jdemon/thaiqr"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/math/f64"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"testing"
+)
+
+const (
+	slipTestPayload      = "004600060000010103001022512345678901234567890123455102TH910408DC"
+	promptPayTestPayload = "00020101021229390016A000000677010111031500499901428007653037645802TH540510.0063046D71"
+)
+
+func qrTestImage(t *testing.T, payload string) image.Image {
+	qrBytes, err := thaiqr.GenerateQR(payload, thaiqr.WithRenderOptions(thaiqr.RenderOptions{Size: 300}))
+	assert.Nil(t, err)
+	img, err := png.Decode(bytes.NewReader(*qrBytes))
+	assert.Nil(t, err)
+	return img
+}
+
+// whiteCanvas returns a white image of the given size, e.g. a screenshot around a QR code.
+func whiteCanvas(width, height int) *image.NRGBA {
+	canvas := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	return canvas
+}
+
+func TestReadImageBytesSlip(t *testing.T) {
+	canvas := whiteCanvas(600, 900)
+	draw.Draw(canvas, image.Rect(150, 500, 450, 800), qrTestImage(t, slipTestPayload), image.Point{}, draw.Over)
+	var buf bytes.Buffer
+	assert.Nil(t, png.Encode(&buf, canvas))
+
+	found, err := thaiqr.ReadImageBytes(buf.Bytes())
+	assert.Nil(t, err)
+	assert.Len(t, found, 1)
+	assert.Nil(t, found[0].Err)
+	assert.Equal(t, slipTestPayload, found[0].Payload)
+	assert.Equal(t, thaiqr.QRTypeVerifyPaySlip, found[0].Parsed.Type)
+	assert.Equal(t, "1234567890123456789012345", found[0].Parsed.VerifyPaySlip.Payload.TransactionRef)
+	for _, p := range found[0].Points {
+		assert.True(t, p.In(image.Rect(150, 500, 450, 800)), p)
+	}
+}
+
+func TestReadImageMultipleCodes(t *testing.T) {
+	canvas := whiteCanvas(700, 400)
+	draw.Draw(canvas, image.Rect(20, 50, 320, 350), qrTestImage(t, slipTestPayload), image.Point{}, draw.Over)
+	draw.Draw(canvas, image.Rect(380, 50, 680, 350), qrTestImage(t, promptPayTestPayload), image.Point{}, draw.Over)
+
+	found, err := thaiqr.ReadImage(canvas)
+	assert.Nil(t, err)
+	types := make(map[string]thaiqr.QRType)
+	for _, qr := range found {
+		assert.Nil(t, qr.Err)
+		types[qr.Payload] = qr.Parsed.Type
+	}
+	assert.Equal(t, map[string]thaiqr.QRType{
+		slipTestPayload:      thaiqr.QRTypeVerifyPaySlip,
+		promptPayTestPayload: thaiqr.QRTypePromptPay,
+	}, types)
+}
+
+func TestReadImageRotatedJPEG(t *testing.T) {
+	qrImage := qrTestImage(t, promptPayTestPayload)
+	canvas := whiteCanvas(600, 600)
+	// rotate by 30 degrees around the centre of the canvas
+	sin, cos := math.Sincos(math.Pi / 6)
+	transform := f64.Aff3{cos, -sin, 300 - 150*cos + 150*sin, sin, cos, 300 - 150*sin - 150*cos}
+	draw.BiLinear.Transform(canvas, transform, qrImage, qrImage.Bounds(), draw.Over, nil)
+	var buf bytes.Buffer
+	assert.Nil(t, jpeg.Encode(&buf, canvas, &jpeg.Options{Quality: 80}))
+
+	found, err := thaiqr.ReadImageBytes(buf.Bytes())
+	assert.Nil(t, err)
+	assert.Len(t, found, 1)
+	assert.Equal(t, thaiqr.QRTypePromptPay, found[0].Parsed.Type)
+	assert.Equal(t, "004999014280076", found[0].Parsed.PromptPay.CreditTransfer.EWalletID)
+}
+
+func TestReadImagePerspective(t *testing.T) {
+	qrImage := qrTestImage(t, slipTestPayload)
+	canvas := whiteCanvas(500, 500)
+	// a photo taken at an angle: the top edge of the code is narrower than the bottom edge
+	for y := 0; y < 500; y++ {
+		for x := 0; x < 500; x++ {
+			w := 1 + 0.0015*float64(y-250)
+			sx := (float64(x)-250)/w + 150
+			sy := (float64(y)-250)/w + 150
+			if sx >= 0 && sy >= 0 && sx < 300 && sy < 300 {
+				canvas.Set(x, y, qrImage.At(int(sx), int(sy)))
+			}
+		}
+	}
+
+	found, err := thaiqr.ReadImage(canvas)
+	assert.Nil(t, err)
+	assert.Len(t, found, 1)
+	assert.Equal(t, thaiqr.QRTypeVerifyPaySlip, found[0].Parsed.Type)
+}
+
+func TestReadImageNoPaymentCode(t *testing.T) {
+	found, err := thaiqr.ReadImage(qrTestImage(t, "https://example.com"))
+	assert.Nil(t, err)
+	assert.Len(t, found, 1)
+	assert.Equal(t, "https://example.com", found[0].Payload)
+	assert.Error(t, found[0].Err)
+
+	_, err = thaiqr.ReadImage(whiteCanvas(200, 200))
+	assert.ErrorIs(t, err, thaiqr.ErrNoQRCode)
+
+	_, err = thaiqr.ReadImageBytes([]byte("not an image"))
+	assert.Error(t, err)
+}
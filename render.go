@@ -0,0 +1,207 @@
+package thaiqr
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	qr "github.com/skip2/go-qrcode"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+)
+
+// ErrorCorrectionLevel is the share of the symbol that can be damaged and still be read.
+type ErrorCorrectionLevel string
+
+const (
+	ErrorCorrectionLow      ErrorCorrectionLevel = "L" // 7%
+	ErrorCorrectionMedium   ErrorCorrectionLevel = "M" // 15%
+	ErrorCorrectionQuartile ErrorCorrectionLevel = "Q" // 25%
+	ErrorCorrectionHigh     ErrorCorrectionLevel = "H" // 30%
+)
+
+const (
+	defaultImageSize = 512
+	defaultQuietZone = 4
+)
+
+// RenderOptions controls how a QR code is rasterised. The zero value renders the
+// default 512px black on white symbol with high error correction and a 4 module quiet zone.
+type RenderOptions struct {
+	// Size is the image width and height in pixels. Modules are scaled to whole pixels
+	// and the remainder is added to the quiet zone. Ignored when ModuleSize is set.
+	Size int
+	// ModuleSize is the width of a single module in pixels; the image size follows from it.
+	ModuleSize int
+	// QuietZone is the margin around the symbol in modules. 0 uses the standard 4 modules,
+	// a negative value renders no quiet zone.
+	QuietZone int
+	// ErrorCorrection defaults to ErrorCorrectionHigh.
+	ErrorCorrection ErrorCorrectionLevel
+	// Foreground and Background default to black and white.
+	Foreground color.Color
+	Background color.Color
+	// Transparent renders the background fully transparent, ignoring Background.
+	Transparent bool
+	// DPI is written to the PNG pHYs chunk so print tools size the image correctly. 0 omits it.
+	DPI int
+	// ModuleShape applies to vector output only; raster images always use square modules.
+	ModuleShape ModuleShape
+}
+
+// DefaultRenderOptions returns the options GenerateQR uses when none are given.
+func DefaultRenderOptions() RenderOptions {
+	return RenderOptions{
+		Size:            defaultImageSize,
+		QuietZone:       defaultQuietZone,
+		ErrorCorrection: ErrorCorrectionHigh,
+		Foreground:      color.Black,
+		Background:      color.White,
+	}
+}
+
+// WithRenderOptions sets how the QR image is rasterised.
+func WithRenderOptions(ro RenderOptions) Option {
+	return func(o *options) {
+		o.render = ro
+	}
+}
+
+// normalize fills the unset fields of the options with their defaults.
+func (ro RenderOptions) normalize() RenderOptions {
+	defaults := DefaultRenderOptions()
+	if ro.Size <= 0 {
+		ro.Size = defaults.Size
+	}
+	if ro.QuietZone == 0 {
+		ro.QuietZone = defaults.QuietZone
+	} else if ro.QuietZone < 0 {
+		ro.QuietZone = 0
+	}
+	if ro.ErrorCorrection == "" {
+		ro.ErrorCorrection = defaults.ErrorCorrection
+	}
+	if ro.Foreground == nil {
+		ro.Foreground = defaults.Foreground
+	}
+	if ro.Background == nil {
+		ro.Background = defaults.Background
+	}
+	if ro.Transparent {
+		ro.Background = color.Transparent
+	}
+	return ro
+}
+
+func (level ErrorCorrectionLevel) recoveryLevel() (qr.RecoveryLevel, error) {
+	switch level {
+	case ErrorCorrectionLow:
+		return qr.Low, nil
+	case ErrorCorrectionMedium:
+		return qr.Medium, nil
+	case ErrorCorrectionQuartile:
+		return qr.High, nil
+	case ErrorCorrectionHigh:
+		return qr.Highest, nil
+	default:
+		return 0, errors.New("invalid error correction level")
+	}
+}
+
+// encodeBitmap encodes content into its module matrix, without quiet zone. bitmap[y][x] is true for dark modules.
+func encodeBitmap(content string, level ErrorCorrectionLevel) ([][]bool, error) {
+	recoveryLevel, err := level.recoveryLevel()
+	if err != nil {
+		return nil, err
+	}
+	code, err := qr.New(content, recoveryLevel)
+	if err != nil {
+		return nil, err
+	}
+	code.DisableBorder = true
+	return code.Bitmap(), nil
+}
+
+// qrGeometry describes where renderImage drew the symbol.
+type qrGeometry struct {
+	// moduleSize is the side of a module in pixels.
+	moduleSize int
+	// quietZone is the blank border around the symbol, in modules.
+	quietZone int
+	// offset is the distance from the image edges to the symbol in pixels. It is larger than
+	// quietZone modules when the image size is not a multiple of the module count.
+	offset int
+	// modules is the width of the symbol in modules, without quiet zone.
+	modules int
+}
+
+// symbol returns the pixel bounds of the symbol, without quiet zone.
+func (g qrGeometry) symbol() image.Rectangle {
+	side := g.modules * g.moduleSize
+	return image.Rect(g.offset, g.offset, g.offset+side, g.offset+side)
+}
+
+// renderImage rasterises content into a two colour image and returns where the symbol lies in it.
+// ro must be normalized.
+func renderImage(content string, ro RenderOptions) (*image.Paletted, qrGeometry, error) {
+	bitmap, err := encodeBitmap(content, ro.ErrorCorrection)
+	if err != nil {
+		return nil, qrGeometry{}, err
+	}
+
+	modules := len(bitmap) + 2*ro.QuietZone
+	moduleSize := ro.ModuleSize
+	size := modules * moduleSize
+	if moduleSize <= 0 {
+		moduleSize = max(ro.Size/modules, 1)
+		size = max(ro.Size, modules)
+	}
+	offset := (size - len(bitmap)*moduleSize) / 2
+
+	img := image.NewPaletted(image.Rect(0, 0, size, size), color.Palette{ro.Background, ro.Foreground})
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+			for py := 0; py < moduleSize; py++ {
+				start := img.PixOffset(offset+x*moduleSize, offset+y*moduleSize+py)
+				for px := 0; px < moduleSize; px++ {
+					img.Pix[start+px] = 1
+				}
+			}
+		}
+	}
+	return img, qrGeometry{moduleSize: moduleSize, quietZone: ro.QuietZone, offset: offset, modules: len(bitmap)}, nil
+}
+
+// encodePNG encodes img as PNG, adding a pHYs chunk when dpi is positive.
+func encodePNG(img image.Image, dpi int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	if dpi <= 0 {
+		return buf.Bytes(), nil
+	}
+
+	// The signature (8 bytes) and IHDR chunk (25 bytes) always come first; pHYs must precede IDAT.
+	const ihdrEnd = 8 + 25
+	data := buf.Bytes()
+	pixelsPerMeter := uint32(math.Round(float64(dpi) / 0.0254))
+
+	chunk := make([]byte, 0, 21)
+	chunk = binary.BigEndian.AppendUint32(chunk, 9)
+	chunk = append(chunk, "pHYs"...)
+	chunk = binary.BigEndian.AppendUint32(chunk, pixelsPerMeter)
+	chunk = binary.BigEndian.AppendUint32(chunk, pixelsPerMeter)
+	chunk = append(chunk, 1) // unit: metre
+	chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+
+	out := make([]byte, 0, len(data)+len(chunk))
+	out = append(out, data[:ihdrEnd]...)
+	out = append(out, chunk...)
+	return append(out, data[ihdrEnd:]...), nil
+}
@@ -0,0 +1,103 @@
+package thaiqr_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/Jdemon/thaiqr"
+	"github.com/stretchr/testify/assert"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestGenerateQRDefaultRenderOptions(t *testing.T) {
+	qrBytes, err := thaiqr.GenerateQR(logoTestPayload)
+	assert.Nil(t, err)
+
+	img, err := png.Decode(bytes.NewReader(*qrBytes))
+	assert.Nil(t, err)
+	assert.Equal(t, 512, img.Bounds().Dx())
+	assert.Equal(t, color.Gray16{Y: 0xffff}, color.Gray16Model.Convert(img.At(0, 0)))
+}
+
+func TestGenerateQRModuleSizeAndQuietZone(t *testing.T) {
+	qrBytes, err := thaiqr.GenerateQR(logoTestPayload, thaiqr.WithRenderOptions(thaiqr.RenderOptions{
+		ModuleSize: 1,
+		QuietZone:  -1,
+	}))
+	assert.Nil(t, err)
+	img, err := png.Decode(bytes.NewReader(*qrBytes))
+	assert.Nil(t, err)
+	symbolSize := img.Bounds().Dx()
+	// symbols are 17+4*version modules wide
+	assert.Equal(t, 0, (symbolSize-17)%4)
+	// the finder pattern starts in the top left corner without a quiet zone
+	r, _, _, _ := img.At(0, 0).RGBA()
+	assert.Equal(t, uint32(0), r)
+
+	qrBytes, err = thaiqr.GenerateQR(logoTestPayload, thaiqr.WithRenderOptions(thaiqr.RenderOptions{
+		ModuleSize: 3,
+		QuietZone:  2,
+	}))
+	assert.Nil(t, err)
+	img, err = png.Decode(bytes.NewReader(*qrBytes))
+	assert.Nil(t, err)
+	assert.Equal(t, (symbolSize+4)*3, img.Bounds().Dx())
+}
+
+func TestGenerateQRErrorCorrectionLevel(t *testing.T) {
+	sizeFor := func(level thaiqr.ErrorCorrectionLevel) int {
+		qrBytes, err := thaiqr.GenerateQR(logoTestPayload, thaiqr.WithRenderOptions(thaiqr.RenderOptions{
+			ModuleSize:      1,
+			QuietZone:       -1,
+			ErrorCorrection: level,
+		}))
+		assert.Nil(t, err)
+		img, err := png.Decode(bytes.NewReader(*qrBytes))
+		assert.Nil(t, err)
+		return img.Bounds().Dx()
+	}
+	assert.Less(t, sizeFor(thaiqr.ErrorCorrectionLow), sizeFor(thaiqr.ErrorCorrectionHigh))
+
+	_, err := thaiqr.GenerateQR(logoTestPayload, thaiqr.WithRenderOptions(thaiqr.RenderOptions{ErrorCorrection: "X"}))
+	assert.Error(t, err)
+}
+
+func TestGenerateQRColoursAndTransparency(t *testing.T) {
+	foreground := color.NRGBA{R: 0x1B, G: 0x3E, B: 0x6F, A: 0xFF}
+	qrBytes, err := thaiqr.GenerateQR(logoTestPayload, thaiqr.WithRenderOptions(thaiqr.RenderOptions{
+		ModuleSize:  2,
+		QuietZone:   -1,
+		Foreground:  foreground,
+		Transparent: true,
+	}))
+	assert.Nil(t, err)
+	img, err := png.Decode(bytes.NewReader(*qrBytes))
+	assert.Nil(t, err)
+	assert.Equal(t, foreground, color.NRGBAModel.Convert(img.At(0, 0)))
+	// the finder pattern has a light ring one module in
+	_, _, _, a := img.At(2, 2).RGBA()
+	assert.Equal(t, uint32(0), a)
+}
+
+func TestGenerateQRDPI(t *testing.T) {
+	qrBytes, err := thaiqr.GenerateQR(logoTestPayload, thaiqr.WithRenderOptions(thaiqr.RenderOptions{DPI: 300}))
+	assert.Nil(t, err)
+
+	data := *qrBytes
+	assert.Equal(t, []byte("pHYs"), data[37:41])
+	// 300 dpi is 11811 pixels per metre
+	assert.Equal(t, uint32(11811), binary.BigEndian.Uint32(data[41:45]))
+	assert.Equal(t, uint32(11811), binary.BigEndian.Uint32(data[45:49]))
+	assert.Equal(t, byte(1), data[49])
+
+	_, err = png.Decode(bytes.NewReader(data))
+	assert.Nil(t, err)
+
+	buf, err := thaiqr.EncodeThaiQRLogo(logoTestPayload, thaiqr.WithRenderOptions(thaiqr.RenderOptions{Size: 256, DPI: 300}))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("pHYs"), buf.Bytes()[37:41])
+	img, err := png.Decode(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, 256, img.Bounds().Dx())
+}
@@ -0,0 +1,96 @@
+package thaiqr
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Scheme is a national variant of the EMV merchant presented mode: the same top level
+// layout with its own merchant account template and local rules. Parse consults the registered
+// schemes, in registration order, before the built-in network readers; a payload claims a scheme
+// only when both Detect and Validate accept it.
+type Scheme interface {
+	// Name identifies the scheme and is reported in Parsed.Scheme.
+	Name() string
+	// Detect reports whether a payload, given its top level fields and segments, belongs to the scheme.
+	Detect(fields map[string]string, segments []Segment) bool
+	// Validate checks the scheme's local rules against the top level fields.
+	Validate(fields map[string]string) error
+	// DecodeTemplate decodes the scheme's merchant account template from the top level fields.
+	DecodeTemplate(fields map[string]string) (any, error)
+	// EncodeTemplate encodes a template value into serialized top level fields, ready to be
+	// appended to the payload data.
+	EncodeTemplate(template any) ([]string, error)
+}
+
+var (
+	schemesMu sync.RWMutex
+	schemes   = []Scheme{
+		PromptPayCreditTransferScheme,
+		PromptPayBillPaymentScheme,
+	}
+)
+
+// RegisterScheme adds a scheme to the registry. Scheme names are unique.
+func RegisterScheme(scheme Scheme) error {
+	if scheme == nil || strings.TrimSpace(scheme.Name()) == "" {
+		return errors.New("invalid scheme")
+	}
+
+	schemesMu.Lock()
+	defer schemesMu.Unlock()
+	for _, registered := range schemes {
+		if registered.Name() == scheme.Name() {
+			return errors.New("scheme already registered")
+		}
+	}
+	schemes = append(schemes, scheme)
+	return nil
+}
+
+// UnregisterScheme removes a scheme added with RegisterScheme. The built-in schemes cannot be removed.
+func UnregisterScheme(name string) error {
+	if name == SchemeNamePromptPayCreditTransfer || name == SchemeNamePromptPayBillPayment {
+		return errors.New("built-in scheme cannot be unregistered")
+	}
+
+	schemesMu.Lock()
+	defer schemesMu.Unlock()
+	for i, registered := range schemes {
+		if registered.Name() == name {
+			schemes = slices.Delete(slices.Clone(schemes), i, i+1)
+			return nil
+		}
+	}
+	return errors.New("scheme not registered")
+}
+
+// Schemes returns the registered schemes in registration order, built-in schemes first.
+func Schemes() []Scheme {
+	schemesMu.RLock()
+	defer schemesMu.RUnlock()
+	return append([]Scheme(nil), schemes...)
+}
+
+// SchemeByName looks up a registered scheme by name.
+func SchemeByName(name string) (Scheme, bool) {
+	for _, scheme := range Schemes() {
+		if scheme.Name() == name {
+			return scheme, true
+		}
+	}
+	return nil, false
+}
+
+// detectScheme returns the first registered scheme that recognises the payload and whose local
+// rules it passes.
+func detectScheme(fields map[string]string, segments []Segment) (Scheme, bool) {
+	for _, scheme := range Schemes() {
+		if scheme.Detect(fields, segments) && scheme.Validate(fields) == nil {
+			return scheme, true
+		}
+	}
+	return nil, false
+}
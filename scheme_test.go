@@ -0,0 +1,180 @@
+package thaiqr_test
+
+import (
+	"errors"
+	"github.com/Jdemon/thaiqr"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+type exampleTemplate struct {
+	AccountID string
+}
+
+// exampleScheme is a third-party scheme carrying an account ID in merchant account template 26.
+type exampleScheme struct{}
+
+func (exampleScheme) Name() string {
+	return "EXAMPLE"
+}
+
+func (exampleScheme) Detect(fields map[string]string, _ []thaiqr.Segment) bool {
+	return strings.HasPrefix(fields["26"], "0015COM.EXAMPLE.PAY")
+}
+
+func (s exampleScheme) Validate(fields map[string]string) error {
+	template, err := s.DecodeTemplate(fields)
+	if err != nil {
+		return err
+	}
+	if template.(*exampleTemplate).AccountID == "" {
+		return errors.New("account id is required")
+	}
+	return nil
+}
+
+func (exampleScheme) DecodeTemplate(fields map[string]string) (any, error) {
+	account := strings.TrimPrefix(fields["26"], "0015COM.EXAMPLE.PAY")
+	if len(account) < 4 {
+		return &exampleTemplate{}, nil
+	}
+	return &exampleTemplate{AccountID: account[4:]}, nil
+}
+
+func (exampleScheme) EncodeTemplate(template any) ([]string, error) {
+	return nil, errors.New("not implemented")
+}
+
+// registerTestScheme registers scheme for the duration of the test.
+func registerTestScheme(t *testing.T, scheme thaiqr.Scheme) {
+	assert.Nil(t, thaiqr.RegisterScheme(scheme))
+	t.Cleanup(func() {
+		assert.Nil(t, thaiqr.UnregisterScheme(scheme.Name()))
+	})
+}
+
+func TestRegisterSchemeParse(t *testing.T) {
+	registerTestScheme(t, exampleScheme{})
+	assert.Error(t, thaiqr.RegisterScheme(exampleScheme{}))
+
+	_, ok := thaiqr.SchemeByName("EXAMPLE")
+	assert.True(t, ok)
+
+	parsed, err := thaiqr.Parse("00020101021126310015COM.EXAMPLE.PAY0108ACC1234553033445802HK5902AB6009Hong Kong63044B5B")
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.QRTypeScheme, parsed.Type)
+	assert.Equal(t, "EXAMPLE", parsed.Scheme)
+	assert.Equal(t, &exampleTemplate{AccountID: "ACC12345"}, parsed.SchemeTemplate)
+	assert.Equal(t, "HK", parsed.EMV.CountryCode)
+
+	// failing the scheme's Validate falls back to the generic EMV reader
+	parsed, err = thaiqr.Parse("00020101021126190015COM.EXAMPLE.PAY53033445802HK5902AB6009Hong Kong63049AF0")
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.QRTypeEMV, parsed.Type)
+	assert.Empty(t, parsed.Scheme)
+}
+
+func TestUnregisterScheme(t *testing.T) {
+	assert.Nil(t, thaiqr.RegisterScheme(exampleScheme{}))
+	assert.Nil(t, thaiqr.UnregisterScheme("EXAMPLE"))
+	_, ok := thaiqr.SchemeByName("EXAMPLE")
+	assert.False(t, ok)
+	assert.Error(t, thaiqr.UnregisterScheme("EXAMPLE"))
+	assert.Error(t, thaiqr.UnregisterScheme(thaiqr.SchemeNamePromptPayCreditTransfer))
+
+	parsed, err := thaiqr.Parse("00020101021126310015COM.EXAMPLE.PAY0108ACC1234553033445802HK5902AB6009Hong Kong63044B5B")
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.QRTypeEMV, parsed.Type)
+}
+
+// registryTestScheme claims the PayNow payloads of one merchant, to check registered schemes are
+// consulted before the built-in network readers.
+type registryTestScheme struct{}
+
+func (registryTestScheme) Name() string {
+	return "REGISTRY_TEST"
+}
+
+func (registryTestScheme) Detect(fields map[string]string, _ []thaiqr.Segment) bool {
+	return fields["59"] == "REGISTRY TEST" && strings.Contains(fields["26"], thaiqr.GUIDPayNow)
+}
+
+func (registryTestScheme) Validate(fields map[string]string) error {
+	if fields["60"] == "" {
+		return errors.New("merchant city is required")
+	}
+	return nil
+}
+
+func (registryTestScheme) DecodeTemplate(fields map[string]string) (any, error) {
+	return fields["59"], nil
+}
+
+func (registryTestScheme) EncodeTemplate(template any) ([]string, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestParseConsultsRegistryFirst(t *testing.T) {
+	registerTestScheme(t, registryTestScheme{})
+
+	cmd := thaiqr.PayNowQRCmd{ProxyType: "UEN", ProxyValue: "201403121W", MerchantName: "REGISTRY TEST", MerchantCity: "Singapore"}
+	payload, err := thaiqr.NewPayNowQR().GeneratePayload(cmd)
+	assert.Nil(t, err)
+	parsed, err := thaiqr.Parse(payload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.QRTypeScheme, parsed.Type)
+	assert.Equal(t, "REGISTRY_TEST", parsed.Scheme)
+	assert.Equal(t, "REGISTRY TEST", parsed.SchemeTemplate)
+
+	// other PayNow payloads still go to the built-in reader
+	cmd.MerchantName = "OTHER SHOP"
+	payload, err = thaiqr.NewPayNowQR().GeneratePayload(cmd)
+	assert.Nil(t, err)
+	parsed, err = thaiqr.Parse(payload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.QRTypePayNow, parsed.Type)
+}
+
+func TestParseValidatesPromptPayScheme(t *testing.T) {
+	// a credit transfer carrying both a mobile number and a national ID is not a valid PromptPay
+	// payload, so it falls back to the generic EMV reader
+	parsed, err := thaiqr.Parse("00020101021129540016A000000677010111011300669097648560213123456789012353037645802TH630467DE")
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.QRTypeEMV, parsed.Type)
+	assert.Nil(t, parsed.PromptPay)
+
+	parsed, err = thaiqr.Parse(promptPayTestPayload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.QRTypePromptPay, parsed.Type)
+}
+
+func TestBuiltinSchemesRegistered(t *testing.T) {
+	schemes := thaiqr.Schemes()
+	assert.GreaterOrEqual(t, len(schemes), 2)
+	assert.Equal(t, thaiqr.SchemeNamePromptPayCreditTransfer, schemes[0].Name())
+	assert.Equal(t, thaiqr.SchemeNamePromptPayBillPayment, schemes[1].Name())
+	assert.Error(t, thaiqr.RegisterScheme(nil))
+}
+
+func TestPromptPaySchemeRoundTrip(t *testing.T) {
+	scheme := thaiqr.PromptPayCreditTransferScheme
+	encoded, err := scheme.EncodeTemplate(&thaiqr.CreditTransfer{AID: thaiqr.GUIDPromptPay, MSISDN: "0066909764856"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"29370016A00000067701011101130066909764856"}, encoded)
+
+	fields := map[string]string{"29": encoded[0][4:]}
+	assert.True(t, scheme.Detect(fields, nil))
+	assert.Nil(t, scheme.Validate(fields))
+
+	template, err := scheme.DecodeTemplate(fields)
+	assert.Nil(t, err)
+	assert.Equal(t, "0066909764856", template.(*thaiqr.CreditTransfer).MSISDN)
+
+	_, err = scheme.EncodeTemplate(&thaiqr.BillPayment{})
+	assert.Error(t, err)
+
+	billFields := map[string]string{"30": "0016A00000067701011202000300"}
+	assert.True(t, thaiqr.PromptPayBillPaymentScheme.Detect(billFields, nil))
+	assert.Error(t, thaiqr.PromptPayBillPaymentScheme.Validate(billFields))
+}
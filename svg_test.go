@@ -0,0 +1,106 @@
+package thaiqr_test
+
+import (
+	"flag"
+	"github.com/Jdemon/thaiqr"
+	"github.com/stretchr/testify/assert"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+const svgTestPayload = "003700040000010103006021620231130773524225102TH9104EC49"
+
+// assertGolden compares actual with testdata/name, rewriting the file when -update is set.
+func assertGolden(t *testing.T, name string, actual []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		assert.Nil(t, os.WriteFile(path, actual, 0o644))
+	}
+	expected, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, string(expected), string(actual))
+}
+
+func TestGenerateSVGGolden(t *testing.T) {
+	tests := []struct {
+		golden string
+		render thaiqr.RenderOptions
+	}{
+		{"qr_square.svg", thaiqr.RenderOptions{ErrorCorrection: thaiqr.ErrorCorrectionLow}},
+		{"qr_circle.svg", thaiqr.RenderOptions{
+			ErrorCorrection: thaiqr.ErrorCorrectionLow,
+			ModuleShape:     thaiqr.ModuleShapeCircle,
+			ModuleSize:      8,
+			QuietZone:       2,
+		}},
+		{"qr_rounded_transparent.svg", thaiqr.RenderOptions{
+			ErrorCorrection: thaiqr.ErrorCorrectionLow,
+			ModuleShape:     thaiqr.ModuleShapeRounded,
+			Foreground:      color.NRGBA{R: 0x11, G: 0x3A, B: 0x6C, A: 0xCC},
+			Transparent:     true,
+			QuietZone:       -1,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			svg, err := thaiqr.GenerateSVG(svgTestPayload, thaiqr.WithRenderOptions(tt.render))
+			assert.Nil(t, err)
+			assertGolden(t, tt.golden, *svg)
+		})
+	}
+}
+
+func TestGenerateSVGWithLogoGolden(t *testing.T) {
+	logo := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x++ {
+		logo.Set(x, 0, color.NRGBA{R: 0xFF, A: 0xFF})
+		logo.Set(x, 1, color.NRGBA{B: 0xFF, A: 0xFF})
+	}
+
+	svg, err := thaiqr.GenerateSVGWithThaiQRLogo(svgTestPayload, thaiqr.WithLogo(logo),
+		thaiqr.WithRenderOptions(thaiqr.RenderOptions{ErrorCorrection: thaiqr.ErrorCorrectionHigh}))
+	assert.Nil(t, err)
+	assertGolden(t, "qr_logo.svg", *svg)
+
+	svg, err = thaiqr.GenerateSVGWithThaiQRLogo(svgTestPayload)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(string(*svg), `xlink:href="data:image/png;base64,`))
+}
+
+func TestGenerateSVGLogoClearsModules(t *testing.T) {
+	logo := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	opaque, err := thaiqr.GenerateSVGWithThaiQRLogo(svgTestPayload, thaiqr.WithLogo(logo))
+	assert.Nil(t, err)
+	// the background rect behind the logo is one module larger on every side
+	assert.Contains(t, string(*opaque), `<rect x="16.2" y="16.2" width="8.6" height="8.6" fill="#FFFFFF"/>`+"\n<image ")
+
+	transparent, err := thaiqr.GenerateSVGWithThaiQRLogo(svgTestPayload, thaiqr.WithLogo(logo),
+		thaiqr.WithRenderOptions(thaiqr.RenderOptions{Transparent: true}))
+	assert.Nil(t, err)
+	assert.NotContains(t, string(*transparent), "<rect")
+	plain, err := thaiqr.GenerateSVG(svgTestPayload, thaiqr.WithRenderOptions(thaiqr.RenderOptions{Transparent: true}))
+	assert.Nil(t, err)
+	assert.Less(t, strings.Count(string(*transparent), "M"), strings.Count(string(*plain), "M"))
+}
+
+func TestGenerateSVGVerify(t *testing.T) {
+	_, err := thaiqr.GenerateSVGWithThaiQRLogo(svgTestPayload, thaiqr.WithVerify())
+	assert.Nil(t, err)
+
+	_, err = thaiqr.GenerateSVG(svgTestPayload, thaiqr.WithVerify(), thaiqr.WithRenderOptions(thaiqr.RenderOptions{
+		Foreground: color.White,
+	}))
+	assert.ErrorIs(t, err, thaiqr.ErrQRNotDecodable)
+}
+
+func TestGenerateSVGInvalid(t *testing.T) {
+	_, err := thaiqr.GenerateSVG(svgTestPayload, thaiqr.WithRenderOptions(thaiqr.RenderOptions{ModuleShape: "star"}))
+	assert.Error(t, err)
+}
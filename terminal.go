@@ -0,0 +1,118 @@
+package thaiqr
+
+import (
+	"errors"
+	"io"
+	"strings"
+)
+
+// TerminalMode selects how WriteTerminal draws modules. Both modes draw two module rows per text
+// line, so modules come out roughly square in a terminal font.
+type TerminalMode string
+
+const (
+	// TerminalModeHalfBlock draws modules with the Unicode ▀ ▄ █ block characters in the terminal's
+	// own text colour. It also works when copied into logs and chat.
+	TerminalModeHalfBlock TerminalMode = "halfblock"
+	// TerminalModeANSI draws modules with ANSI black and white colours, whatever the terminal theme.
+	TerminalModeANSI TerminalMode = "ansi"
+)
+
+const (
+	ansiReset        = "\x1b[0m"
+	ansiDarkOnLight  = "\x1b[30;47m"
+	ansiLightOnDark  = "\x1b[37;40m"
+	ansiDarkOnDark   = "\x1b[30;40m"
+	ansiLightOnLight = "\x1b[37;47m"
+)
+
+// TerminalOptions controls how a QR code is printed as text. Quiet zone and error correction
+// come from WithRenderOptions.
+type TerminalOptions struct {
+	// Mode defaults to TerminalModeHalfBlock.
+	Mode TerminalMode
+	// Invert swaps dark and light modules. Use it for half blocks on terminals with light text on a
+	// dark background, where the blocks would otherwise print the light modules.
+	Invert bool
+}
+
+// GenerateTerminal renders payload as text that can be scanned straight from a terminal.
+func GenerateTerminal(payload string, to TerminalOptions, opts ...Option) (string, error) {
+	var sb strings.Builder
+	if err := WriteTerminal(&sb, payload, to, opts...); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// WriteTerminal writes payload as text that can be scanned straight from a terminal, e.g. os.Stdout.
+func WriteTerminal(w io.Writer, payload string, to TerminalOptions, opts ...Option) error {
+	ro := newOptions(opts).render.normalize()
+	bitmap, err := encodeBitmap(payload, ro.ErrorCorrection)
+	if err != nil {
+		return err
+	}
+
+	size := len(bitmap) + 2*ro.QuietZone
+	dark := func(x, y int) bool {
+		x, y = x-ro.QuietZone, y-ro.QuietZone
+		isDark := y >= 0 && y < len(bitmap) && x >= 0 && x < len(bitmap) && bitmap[y][x]
+		return isDark != to.Invert
+	}
+
+	var sb strings.Builder
+	for y := 0; y < size; y += 2 {
+		switch to.Mode {
+		case "", TerminalModeHalfBlock:
+			for x := 0; x < size; x++ {
+				sb.WriteString(halfBlock(dark(x, y), y+1 < size && dark(x, y+1)))
+			}
+		case TerminalModeANSI:
+			current := ""
+			for x := 0; x < size; x++ {
+				// ▀ is drawn in the foreground colour over the background colour of the lower half
+				colours := ansiColours(dark(x, y), y+1 < size && dark(x, y+1))
+				if colours != current {
+					sb.WriteString(colours)
+					current = colours
+				}
+				sb.WriteString("▀")
+			}
+			sb.WriteString(ansiReset)
+		default:
+			return errors.New("invalid terminal mode")
+		}
+		sb.WriteString("\n")
+	}
+
+	_, err = io.WriteString(w, sb.String())
+	return err
+}
+
+// halfBlock returns the character printing the given upper and lower modules in the text colour.
+func halfBlock(upper, lower bool) string {
+	switch {
+	case upper && lower:
+		return "█"
+	case upper:
+		return "▀"
+	case lower:
+		return "▄"
+	default:
+		return " "
+	}
+}
+
+// ansiColours returns the escape sequence colouring ▀ for the given upper and lower modules.
+func ansiColours(upper, lower bool) string {
+	switch {
+	case upper && lower:
+		return ansiDarkOnDark
+	case upper:
+		return ansiDarkOnLight
+	case lower:
+		return ansiLightOnDark
+	default:
+		return ansiLightOnLight
+	}
+}
@@ -0,0 +1,101 @@
+package thaiqr_test
+
+import (
+	"github.com/Jdemon/thaiqr"
+	"github.com/stretchr/testify/assert"
+	"image"
+	"image/color"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// textQRImage draws terminal output back into an image, 4 pixels per module, reading each text cell
+// as an upper and a lower module.
+func textQRImage(lines []string, cell func(line string) [][2]bool, invert bool) image.Image {
+	width := len(cell(lines[0]))
+	img := image.NewGray(image.Rect(0, 0, width*4, len(lines)*8))
+	for y, line := range lines {
+		for x, modules := range cell(line) {
+			for half, dark := range modules {
+				c := color.Gray{Y: 0xff}
+				if dark != invert {
+					c = color.Gray{}
+				}
+				for py := 0; py < 4; py++ {
+					for px := 0; px < 4; px++ {
+						img.SetGray(x*4+px, y*8+half*4+py, c)
+					}
+				}
+			}
+		}
+	}
+	return img
+}
+
+func halfBlockCells(line string) [][2]bool {
+	cells := make([][2]bool, 0)
+	for _, r := range line {
+		cells = append(cells, [2]bool{r == '█' || r == '▀', r == '█' || r == '▄'})
+	}
+	return cells
+}
+
+var ansiCell = regexp.MustCompile(`(?:\x1b\[(3[07]);(4[07])m)?▀`)
+
+func ansiCells(line string) [][2]bool {
+	cells := make([][2]bool, 0)
+	var current [2]bool
+	for _, match := range ansiCell.FindAllStringSubmatch(line, -1) {
+		if match[1] != "" {
+			current = [2]bool{match[1] == "30", match[2] == "40"}
+		}
+		cells = append(cells, current)
+	}
+	return cells
+}
+
+func terminalLines(t *testing.T, to thaiqr.TerminalOptions) []string {
+	text, err := thaiqr.GenerateTerminal(promptPayTestPayload, to,
+		thaiqr.WithRenderOptions(thaiqr.RenderOptions{ErrorCorrection: thaiqr.ErrorCorrectionLow}))
+	assert.Nil(t, err)
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+func TestGenerateTerminalHalfBlockIsScannable(t *testing.T) {
+	for _, invert := range []bool{false, true} {
+		lines := terminalLines(t, thaiqr.TerminalOptions{Invert: invert})
+		// the 4 module quiet zone takes the first two lines
+		quietZone := " "
+		if invert {
+			quietZone = "█"
+		}
+		assert.Equal(t, strings.Repeat(quietZone, len([]rune(lines[0]))), lines[0])
+
+		found, err := thaiqr.ReadImage(textQRImage(lines, halfBlockCells, invert))
+		assert.Nil(t, err)
+		assert.Len(t, found, 1)
+		assert.Equal(t, promptPayTestPayload, found[0].Payload)
+	}
+}
+
+func TestGenerateTerminalANSIIsScannable(t *testing.T) {
+	lines := terminalLines(t, thaiqr.TerminalOptions{Mode: thaiqr.TerminalModeANSI})
+	for _, line := range lines {
+		assert.True(t, strings.HasSuffix(line, "\x1b[0m"))
+	}
+
+	found, err := thaiqr.ReadImage(textQRImage(lines, ansiCells, false))
+	assert.Nil(t, err)
+	assert.Len(t, found, 1)
+	assert.Equal(t, promptPayTestPayload, found[0].Payload)
+}
+
+func TestGenerateTerminalInvalid(t *testing.T) {
+	_, err := thaiqr.GenerateTerminal(promptPayTestPayload, thaiqr.TerminalOptions{Mode: "sixel"})
+	assert.Error(t, err)
+
+	_, err = thaiqr.GenerateTerminal(promptPayTestPayload, thaiqr.TerminalOptions{},
+		thaiqr.WithRenderOptions(thaiqr.RenderOptions{ErrorCorrection: "X"}))
+	assert.Error(t, err)
+}
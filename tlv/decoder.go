@@ -0,0 +1,74 @@
+package tlv
+
+// Decoder iterates over the data objects of an encoded string. Field values are substrings
+// of the input, so decoding does not copy the data.
+type Decoder struct {
+	data  string
+	pos   int
+	base  int
+	field Field
+	err   error
+}
+
+// NewDecoder returns a Decoder reading data.
+func NewDecoder(data string) *Decoder {
+	return &Decoder{data: data}
+}
+
+// Next advances to the next data object. It returns false at the end of the data or on a
+// malformed data object, in which case Err reports the error.
+func (d *Decoder) Next() bool {
+	if d.err != nil || d.pos >= len(d.data) {
+		return false
+	}
+
+	remain := d.data[d.pos:]
+	if len(remain) < 4 || !isDigits(remain[:2]) || !isDigits(remain[2:4]) {
+		d.err = ErrInvalidFormat
+		return false
+	}
+	length := int(remain[2]-'0')*10 + int(remain[3]-'0')
+	if len(remain) < 4+length {
+		d.err = ErrInvalidFormat
+		return false
+	}
+
+	d.field = Field{
+		ID:     remain[:2],
+		Length: length,
+		Value:  remain[4 : 4+length],
+		Raw:    remain[:4+length],
+		Offset: d.base + d.pos,
+	}
+	d.pos += 4 + length
+	return true
+}
+
+// Field returns the data object Next advanced to.
+func (d *Decoder) Field() Field {
+	return d.field
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (d *Decoder) Err() error {
+	return d.err
+}
+
+// Template returns a Decoder over the value of the current data object, for reading a
+// nested template. Offsets of its fields stay relative to the outer data.
+func (d *Decoder) Template() *Decoder {
+	return &Decoder{data: d.field.Value, base: d.field.Offset + 4}
+}
+
+// Decode decodes all data objects of data.
+func Decode(data string) ([]Field, error) {
+	fields := make([]Field, 0)
+	decoder := NewDecoder(data)
+	for decoder.Next() {
+		fields = append(fields, decoder.Field())
+	}
+	if err := decoder.Err(); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
@@ -0,0 +1,60 @@
+package tlv
+
+import (
+	"io"
+)
+
+// Encoder builds a sequence of data objects. The zero value is ready to use.
+type Encoder struct {
+	buf []byte
+}
+
+// NewEncoder returns a new Encoder.
+func NewEncoder() *Encoder {
+	return &Encoder{}
+}
+
+// Field appends a data object. It fails if the ID is not two digits or the value is longer than MaxLength.
+func (e *Encoder) Field(id, value string) error {
+	buf, err := AppendField(e.buf, id, value)
+	if err != nil {
+		return err
+	}
+	e.buf = buf
+	return nil
+}
+
+// Template appends a data object whose value is the nested data objects written by build.
+func (e *Encoder) Template(id string, build func(*Encoder) error) error {
+	nested := &Encoder{}
+	if err := build(nested); err != nil {
+		return err
+	}
+	return e.Field(id, nested.String())
+}
+
+// Bytes returns the encoded data. The slice aliases the encoder's buffer until the next write.
+func (e *Encoder) Bytes() []byte {
+	return e.buf
+}
+
+// String returns the encoded data.
+func (e *Encoder) String() string {
+	return string(e.buf)
+}
+
+// Len returns the number of encoded bytes.
+func (e *Encoder) Len() int {
+	return len(e.buf)
+}
+
+// Reset discards the encoded data, keeping the buffer for reuse.
+func (e *Encoder) Reset() {
+	e.buf = e.buf[:0]
+}
+
+// WriteTo writes the encoded data to w.
+func (e *Encoder) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(e.buf)
+	return int64(n), err
+}
@@ -0,0 +1,52 @@
+// Package tlv encodes and decodes the TLV format of EMV merchant presented QR codes: a two
+// digit ID, a two digit length and the value. Templates nest the same format inside a value.
+package tlv
+
+import (
+	"errors"
+)
+
+// MaxLength is the longest value a two digit length can describe.
+const MaxLength = 99
+
+var (
+	ErrInvalidID     = errors.New("invalid id")
+	ErrValueTooLong  = errors.New("value too long")
+	ErrInvalidFormat = errors.New("invalid format")
+)
+
+// Field is a single decoded data object.
+type Field struct {
+	ID     string `json:"id"`
+	Length int    `json:"length"`
+	Value  string `json:"value"`
+	// Raw is the whole data object: ID, length and value.
+	Raw string `json:"raw"`
+	// Offset is the position of the ID within the data the decoder started from.
+	Offset int `json:"offset"`
+}
+
+// AppendField appends the encoded data object to dst and returns the extended slice.
+func AppendField(dst []byte, id, value string) ([]byte, error) {
+	if !isDigits(id) {
+		return dst, ErrInvalidID
+	}
+	if len(value) > MaxLength {
+		return dst, ErrValueTooLong
+	}
+	dst = append(dst, id...)
+	dst = append(dst, byte('0'+len(value)/10), byte('0'+len(value)%10))
+	return append(dst, value...), nil
+}
+
+func isDigits(s string) bool {
+	if len(s) != 2 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
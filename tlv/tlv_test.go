@@ -0,0 +1,152 @@
+package tlv_test
+
+import (
+	"bytes"
+	"github.com/Jdemon/thaiqr"
+	"github.com/Jdemon/thaiqr/tlv"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestEncoderMustValid(t *testing.T) {
+	encoder := tlv.NewEncoder()
+	assert.Nil(t, encoder.Field("00", "01"))
+	assert.Nil(t, encoder.Template("29", func(template *tlv.Encoder) error {
+		if err := template.Field("00", "A000000677010111"); err != nil {
+			return err
+		}
+		return template.Field("01", "0066909764856")
+	}))
+	assert.Equal(t, "00020129370016A00000067701011101130066909764856", encoder.String())
+
+	var buf bytes.Buffer
+	n, err := encoder.WriteTo(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(encoder.Len()), n)
+	assert.Equal(t, encoder.String(), buf.String())
+
+	encoder.Reset()
+	assert.Equal(t, 0, encoder.Len())
+}
+
+func TestEncoderInvalid(t *testing.T) {
+	encoder := tlv.NewEncoder()
+	assert.ErrorIs(t, encoder.Field("1", "x"), tlv.ErrInvalidID)
+	assert.ErrorIs(t, encoder.Field("AB", "x"), tlv.ErrInvalidID)
+	assert.ErrorIs(t, encoder.Field("59", strings.Repeat("x", 100)), tlv.ErrValueTooLong)
+	assert.Nil(t, encoder.Field("59", strings.Repeat("x", 99)))
+	assert.Equal(t, "5999", encoder.String()[:4])
+}
+
+func TestDecoderMustValid(t *testing.T) {
+	data := "00020129370016A00000067701011101130066909764856"
+	decoder := tlv.NewDecoder(data)
+
+	assert.True(t, decoder.Next())
+	assert.Equal(t, tlv.Field{ID: "00", Length: 2, Value: "01", Raw: "000201", Offset: 0}, decoder.Field())
+
+	assert.True(t, decoder.Next())
+	assert.Equal(t, "29", decoder.Field().ID)
+	assert.Equal(t, 6, decoder.Field().Offset)
+
+	template := decoder.Template()
+	assert.True(t, template.Next())
+	assert.Equal(t, tlv.Field{ID: "00", Length: 16, Value: "A000000677010111", Raw: "0016A000000677010111", Offset: 10}, template.Field())
+	assert.True(t, template.Next())
+	assert.Equal(t, 30, template.Field().Offset)
+	assert.Equal(t, data[34:], template.Field().Value)
+	assert.False(t, template.Next())
+	assert.Nil(t, template.Err())
+
+	assert.False(t, decoder.Next())
+	assert.Nil(t, decoder.Err())
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	_, err := tlv.Decode("0002")
+	assert.ErrorIs(t, err, tlv.ErrInvalidFormat)
+	_, err = tlv.Decode("00-1")
+	assert.ErrorIs(t, err, tlv.ErrInvalidFormat)
+	_, err = tlv.Decode("000201ab")
+	assert.ErrorIs(t, err, tlv.ErrInvalidFormat)
+
+	fields, err := tlv.Decode("")
+	assert.Nil(t, err)
+	assert.Empty(t, fields)
+}
+
+func TestAppendField(t *testing.T) {
+	dst, err := tlv.AppendField([]byte("000201"), "59", "SHOP")
+	assert.Nil(t, err)
+	assert.Equal(t, "0002015904SHOP", string(dst))
+
+	_, err = tlv.AppendField(nil, "59", strings.Repeat("x", tlv.MaxLength+1))
+	assert.ErrorIs(t, err, tlv.ErrValueTooLong)
+}
+
+// TestGeneratorsValueTooLong checks every payload generator reports a value, or a template
+// built from values that each fit, exceeding the two digit length of AppendField.
+func TestGeneratorsValueTooLong(t *testing.T) {
+	long := strings.Repeat("A", tlv.MaxLength+1)
+	payNow := thaiqr.MerchantAccount{ID: "26", GUID: thaiqr.GUIDPayNow, Fields: map[string]string{"01": "2", "02": "T08GB0001A"}}
+	tests := []struct {
+		name     string
+		generate func() (string, error)
+		err      string
+	}{
+		{"PromptPay", func() (string, error) {
+			return thaiqr.NewPromptPayQR().GeneratePayload(thaiqr.PromptPayQRCmd{ProxyID: "0909764856", OTA: long})
+		}, "field 05: value too long"},
+		{"PromptPayBillPayment", func() (string, error) {
+			return thaiqr.NewPromptPayQR().GenerateBillPaymentPayload(thaiqr.PromptPayBillPaymentQRCmd{
+				BillerID: "0105556123456", Ref1: strings.Repeat("1", 20), Ref2: strings.Repeat("2", 60),
+			})
+		}, "field 30: value too long"},
+		{"CrossBorder", func() (string, error) {
+			return thaiqr.NewPromptPayQR().GenerateCrossBorderPayload(thaiqr.PromptPayCrossBorderQRCmd{
+				ProxyID: "0909764856", CurrencyCode: "SGD", MerchantName: long, MerchantCity: "BANGKOK",
+				MerchantAccounts: []thaiqr.MerchantAccount{payNow},
+			})
+		}, "field 59: value too long"},
+		{"PayNow", func() (string, error) {
+			return thaiqr.NewPayNowQR().GeneratePayload(thaiqr.PayNowQRCmd{ProxyType: "UEN", ProxyValue: "201403121W", BillNumber: long})
+		}, "field 01: value too long"},
+		{"DuitNow", func() (string, error) {
+			return thaiqr.NewDuitNowQR().GeneratePayload(thaiqr.DuitNowQRCmd{AcquirerID: "890053", MerchantID: "MBBQR1234567", MerchantName: long})
+		}, "field 59: value too long"},
+		{"QRIS", func() (string, error) {
+			return thaiqr.NewQRISQR().GeneratePayload(thaiqr.QRISQRCmd{
+				NMID: "ID1020021181745", MerchantCriteria: thaiqr.QRISMerchantCriteriaMicro,
+				Acquirers: []thaiqr.QRISAcquirer{{Domain: "ID.CO.BANKMANDIRI.WWW", MerchantPAN: "936000080000000001", MerchantID: strings.Repeat("1", 70)}},
+			})
+		}, "field 26: value too long"},
+		{"VietQR", func() (string, error) {
+			return thaiqr.NewVietQR().GeneratePayload(thaiqr.VietQRCmd{BankBIN: "970436", AccountNumber: "0011001234567", PurposeOfTransaction: long})
+		}, "field 08: value too long"},
+		{"KHQR", func() (string, error) {
+			return thaiqr.NewKHQR().GeneratePayload(thaiqr.KHQRCmd{BakongAccountID: "sokha@aclb", MerchantName: long})
+		}, "field 59: value too long"},
+		{"LaoQR", func() (string, error) {
+			return thaiqr.NewLaoQR().GeneratePayload(thaiqr.LaoQRCmd{MemberBankID: "001", MerchantID: "LA000123", MerchantName: long})
+		}, "field 59: value too long"},
+		{"QRPh", func() (string, error) {
+			return thaiqr.NewQRPh().GeneratePayload(thaiqr.QRPhCmd{AcquirerID: "BNORPHMMXXX", MobileNumber: "09171234567", MerchantName: long})
+		}, "field 59: value too long"},
+		{"MMQR", func() (string, error) {
+			return thaiqr.NewMMQR().GeneratePayload(thaiqr.MMQRCmd{AcquirerID: "KBZ", MerchantID: "M0001", MerchantName: long})
+		}, "field 59: value too long"},
+		{"VerifyPaySlip", func() (string, error) {
+			return thaiqr.NewVerifyPaySlipQR().GeneratePayload(thaiqr.VerifyPaySlipQRCmd{
+				TransactionRef: strings.Repeat("1", 90), SendingBankID: "001", CountryCode: thaiqr.CountryCodeTH,
+			})
+		}, "field 00: value too long"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.generate()
+			assert.ErrorIs(t, err, tlv.ErrValueTooLong)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
@@ -2,7 +2,6 @@ package thaiqr
 
 import (
 	"errors"
-	"fmt"
 )
 
 const (
@@ -51,21 +50,16 @@ func NewVerifyPaySlipQR() *VerifyPaySlipQR {
 // GeneratePayload generates a VerifyPaySlip QR code payload.
 func (qr *VerifyPaySlipQR) GeneratePayload(cmd VerifyPaySlipQRCmd) (string, error) {
 	sendingBankID := sanitizeTarget(cmd.SendingBankID)
-	payload := []string{
-		formatField(IDPayloadAPIID, VerifyPaySlipAPIID),
-		formatField(IDPayloadSendingBankID, sendingBankID),
-		formatField(IDPayloadTransactionRef, cmd.TransactionRef),
-	}
-
-	data := []string{
-		formatField(IDQrVerifyPayload, serialize(payload)),
-		formatField(IDQrVerifyCountryCode, cmd.CountryCode),
-	}
+	payload := &payloadBuilder{}
+	payload.field(IDPayloadAPIID, VerifyPaySlipAPIID)
+	payload.field(IDPayloadSendingBankID, sendingBankID)
+	payload.field(IDPayloadTransactionRef, cmd.TransactionRef)
 
-	dataToCrc := fmt.Sprintf("%s%s%s", serialize(data), IDQrVerifyCRC, "04")
-	data = append(data, formatField(IDQrVerifyCRC, checksum([]byte(dataToCrc))))
+	data := &payloadBuilder{}
+	data.template(IDQrVerifyPayload, payload)
+	data.field(IDQrVerifyCountryCode, cmd.CountryCode)
 
-	return serialize(data), nil
+	return data.withChecksum(IDQrVerifyCRC)
 }
 
 func (qr *VerifyPaySlipQR) Reader(data string) (*VerifyPaySlipQRResult, error) {
@@ -0,0 +1,214 @@
+package thaiqr
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	// VietQRIDGUID VietQR Merchant Account Information Tag 38
+	VietQRIDGUID               = "00"
+	VietQRIDBeneficiary        = "01"
+	VietQRIDServiceCode        = "02"
+	VietQRIDBeneficiaryBIN     = "00"
+	VietQRIDBeneficiaryAccount = "01"
+
+	VietQRIDTag62PurposeOfTransaction = "08"
+
+	IDMerchantInformationVietQR = "38"
+	TransactionCurrencyVND      = "704"
+	CountryCodeVN               = "VN"
+
+	VietQRServiceCodeAccount = "QRIBFTTA"
+	VietQRServiceCodeCard    = "QRIBFTTC"
+)
+
+// VietnamBank is a NAPAS member bank identified by its bank identification number.
+type VietnamBank struct {
+	BIN       string `json:"bin"`
+	ShortName string `json:"shortName"`
+	Name      string `json:"name"`
+}
+
+var vietnamBanks = []VietnamBank{
+	{BIN: "970405", ShortName: "Agribank", Name: "Vietnam Bank for Agriculture and Rural Development"},
+	{BIN: "970407", ShortName: "Techcombank", Name: "Vietnam Technological and Commercial Joint Stock Bank"},
+	{BIN: "970415", ShortName: "VietinBank", Name: "Vietnam Joint Stock Commercial Bank for Industry and Trade"},
+	{BIN: "970416", ShortName: "ACB", Name: "Asia Commercial Joint Stock Bank"},
+	{BIN: "970418", ShortName: "BIDV", Name: "Joint Stock Commercial Bank for Investment and Development of Vietnam"},
+	{BIN: "970422", ShortName: "MBBank", Name: "Military Commercial Joint Stock Bank"},
+	{BIN: "970423", ShortName: "TPBank", Name: "Tien Phong Commercial Joint Stock Bank"},
+	{BIN: "970426", ShortName: "MSB", Name: "Vietnam Maritime Commercial Joint Stock Bank"},
+	{BIN: "970431", ShortName: "Eximbank", Name: "Vietnam Export Import Commercial Joint Stock Bank"},
+	{BIN: "970432", ShortName: "VPBank", Name: "Vietnam Prosperity Joint Stock Commercial Bank"},
+	{BIN: "970436", ShortName: "Vietcombank", Name: "Joint Stock Commercial Bank for Foreign Trade of Vietnam"},
+	{BIN: "970437", ShortName: "HDBank", Name: "Ho Chi Minh City Development Joint Stock Commercial Bank"},
+	{BIN: "970441", ShortName: "VIB", Name: "Vietnam International Commercial Joint Stock Bank"},
+	{BIN: "970443", ShortName: "SHB", Name: "Saigon - Hanoi Commercial Joint Stock Bank"},
+	{BIN: "970448", ShortName: "OCB", Name: "Orient Commercial Joint Stock Bank"},
+	{BIN: "970403", ShortName: "Sacombank", Name: "Saigon Thuong Tin Commercial Joint Stock Bank"},
+}
+
+// VietnamBanks returns the NAPAS member banks known to the VietQR registry.
+func VietnamBanks() []VietnamBank {
+	return slices.Clone(vietnamBanks)
+}
+
+// VietnamBankByBIN looks up a NAPAS member bank by its BIN.
+func VietnamBankByBIN(bin string) (VietnamBank, bool) {
+	for _, bank := range vietnamBanks {
+		if bank.BIN == bin {
+			return bank, true
+		}
+	}
+	return VietnamBank{}, false
+}
+
+// VietnamBankByShortName looks up a NAPAS member bank by its short name, ignoring case.
+func VietnamBankByShortName(name string) (VietnamBank, bool) {
+	for _, bank := range vietnamBanks {
+		if strings.EqualFold(bank.ShortName, name) {
+			return bank, true
+		}
+	}
+	return VietnamBank{}, false
+}
+
+type VietQRCmd struct {
+	BankBIN              string `json:"bankBin"`
+	AccountNumber        string `json:"accountNumber"`
+	ServiceCode          string `json:"serviceCode"`
+	Amount               string `json:"amount"`
+	PurposeOfTransaction string `json:"purposeOfTransaction"`
+}
+
+type VietQRResults struct {
+	PayloadFormatIndicator  string     `json:"payloadFormatIndicator"`
+	PointOfInitiationMethod string     `json:"pointOfInitiationMethod"`
+	GUID                    string     `json:"guid"`
+	BankBIN                 string     `json:"bankBin"`
+	BankName                string     `json:"bankName,omitempty"`
+	AccountNumber           string     `json:"accountNumber"`
+	ServiceCode             string     `json:"serviceCode"`
+	TransactionCurrency     string     `json:"transactionCurrency"`
+	TransactionCurrencyCode string     `json:"transactionCurrencyCode"`
+	TransactionAmount       string     `json:"transactionAmount,omitempty"`
+	CountryCode             string     `json:"countryCode"`
+	MerchantName            string     `json:"merchantName,omitempty"`
+	MerchantCity            string     `json:"merchantCity,omitempty"`
+	PurposeOfTransaction    string     `json:"purposeOfTransaction,omitempty"`
+	CRC                     string     `json:"crc"`
+	Segments                *[]Segment `json:"segments,omitempty"`
+}
+
+// VietQR represents a Vietnam VietQR (NAPAS) QR code generator.
+type VietQR struct{}
+
+// NewVietQR returns a new VietQR instance.
+func NewVietQR() *VietQR {
+	return &VietQR{}
+}
+
+var vietQRAccountPattern = regexp.MustCompile(`^[0-9A-Za-z]{1,19}$`)
+
+// GeneratePayload generates a VietQR payload. The bank is given by its BIN or, failing that, its short name.
+// VND has no minor units, so the amount must be a whole number.
+func (qr *VietQR) GeneratePayload(cmd VietQRCmd) (string, error) {
+	bank, ok := VietnamBankByBIN(strings.TrimSpace(cmd.BankBIN))
+	if !ok {
+		bank, ok = VietnamBankByShortName(strings.TrimSpace(cmd.BankBIN))
+	}
+	if !ok {
+		return "", errors.New("unknown bank bin")
+	}
+	if !vietQRAccountPattern.MatchString(cmd.AccountNumber) {
+		return "", errors.New("invalid account number")
+	}
+	serviceCode := ifThenElse(cmd.ServiceCode != "", cmd.ServiceCode, VietQRServiceCodeAccount).(string)
+	if serviceCode != VietQRServiceCodeAccount && serviceCode != VietQRServiceCodeCard {
+		return "", errors.New("invalid service code")
+	}
+
+	amount := strings.TrimSpace(cmd.Amount)
+	beneficiaryData := &payloadBuilder{}
+	beneficiaryData.field(VietQRIDBeneficiaryBIN, bank.BIN)
+	beneficiaryData.field(VietQRIDBeneficiaryAccount, cmd.AccountNumber)
+
+	merchantInfoData := &payloadBuilder{}
+	merchantInfoData.field(VietQRIDGUID, GUIDNAPAS)
+	merchantInfoData.template(VietQRIDBeneficiary, beneficiaryData)
+	merchantInfoData.field(VietQRIDServiceCode, serviceCode)
+
+	data := &payloadBuilder{}
+	data.field(IDPayloadFormat, PayloadFormatEMVQRCPSMerchantPresentedMode)
+	data.field(IDPOIMethod, ifThenElse(amount != "", POIMethodDynamic, POIMethodStatic).(string))
+	data.template(IDMerchantInformationVietQR, merchantInfoData)
+	data.field(IDTransactionCurrency, TransactionCurrencyVND)
+	if amount != "" {
+		amountFormat, err := formatAmountWithExponent(amount, 0)
+		if err != nil {
+			return "", err
+		}
+		data.field(IDTransactionAmount, amountFormat)
+	}
+	data.field(IDCountryCode, CountryCodeVN)
+	if strings.TrimSpace(cmd.PurposeOfTransaction) != "" {
+		additionalData := &payloadBuilder{}
+		additionalData.field(VietQRIDTag62PurposeOfTransaction, cmd.PurposeOfTransaction)
+		data.template(IDAdditionalFields, additionalData)
+	}
+
+	return data.withChecksum(IDCRC)
+}
+
+// Reader decodes a VietQR payload.
+func (qr *VietQR) Reader(data string) (*VietQRResults, error) {
+	qrFields, qrSegments, err := deserializeMerchantPresented(data)
+	if err != nil {
+		return nil, err
+	}
+
+	_, merchantFields, _, ok := findMerchantAccount(qrSegments, GUIDNAPAS)
+	if !ok {
+		return nil, errors.New("napas merchant account not found")
+	}
+	beneficiaryFields, _, err := deserialize(merchantFields[VietQRIDBeneficiary])
+	if err != nil {
+		return nil, err
+	}
+
+	transactionCurrency := qrFields[IDTransactionCurrency]
+	if transactionCurrency != TransactionCurrencyVND {
+		return nil, errors.New("invalid currency")
+	}
+	countryCode := qrFields[IDCountryCode]
+	if len(countryCode) != 2 {
+		return nil, invalidFormat()
+	}
+
+	additionalFields, _, _ := deserialize(qrFields[IDAdditionalFields])
+
+	bankBIN := beneficiaryFields[VietQRIDBeneficiaryBIN]
+	bank, _ := VietnamBankByBIN(bankBIN)
+
+	return &VietQRResults{
+		PayloadFormatIndicator:  qrFields[IDPayloadFormat],
+		PointOfInitiationMethod: qrFields[IDPOIMethod],
+		GUID:                    merchantFields[VietQRIDGUID],
+		BankBIN:                 bankBIN,
+		BankName:                bank.ShortName,
+		AccountNumber:           beneficiaryFields[VietQRIDBeneficiaryAccount],
+		ServiceCode:             merchantFields[VietQRIDServiceCode],
+		TransactionCurrency:     transactionCurrency,
+		TransactionCurrencyCode: GetCurrencyCode(transactionCurrency),
+		TransactionAmount:       qrFields[IDTransactionAmount],
+		CountryCode:             countryCode,
+		MerchantName:            qrFields[IDMerchantName],
+		MerchantCity:            qrFields[IDMerchantCity],
+		PurposeOfTransaction:    additionalFields[VietQRIDTag62PurposeOfTransaction],
+		CRC:                     qrFields[IDCRC],
+		Segments:                &qrSegments,
+	}, nil
+}
@@ -0,0 +1,92 @@
+package thaiqr_test
+
+import (
+	"github.com/Jdemon/thaiqr"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGenerateVietQRMustValid(t *testing.T) {
+	qr := thaiqr.NewVietQR()
+	cmd := thaiqr.VietQRCmd{
+		BankBIN:              "970436",
+		AccountNumber:        "0011001234567",
+		Amount:               "150000",
+		PurposeOfTransaction: "PHO HANOI",
+	}
+	actualPayload, err := qr.GeneratePayload(cmd)
+	assert.Nil(t, err)
+	assert.Equal(t, "00020101021238570010A000000727012700069704360113001100123456702"+
+		"08QRIBFTTA530370454061500005802VN62130809PHO HANOI6304"+actualPayload[len(actualPayload)-4:], actualPayload)
+
+	result, err := qr.Reader(actualPayload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.GUIDNAPAS, result.GUID)
+	assert.Equal(t, cmd.BankBIN, result.BankBIN)
+	assert.Equal(t, "Vietcombank", result.BankName)
+	assert.Equal(t, cmd.AccountNumber, result.AccountNumber)
+	assert.Equal(t, thaiqr.VietQRServiceCodeAccount, result.ServiceCode)
+	assert.Equal(t, cmd.Amount, result.TransactionAmount)
+	assert.Equal(t, "VND", result.TransactionCurrencyCode)
+	assert.Equal(t, thaiqr.CountryCodeVN, result.CountryCode)
+	assert.Equal(t, cmd.PurposeOfTransaction, result.PurposeOfTransaction)
+
+	parsed, err := thaiqr.Parse(actualPayload)
+	assert.Nil(t, err)
+	assert.Equal(t, thaiqr.QRTypeVietQR, parsed.Type)
+
+	image, err := thaiqr.GenerateQR(actualPayload)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, *image)
+}
+
+func TestGenerateVietQRByBankShortName(t *testing.T) {
+	actualPayload, err := thaiqr.NewVietQR().GeneratePayload(thaiqr.VietQRCmd{
+		BankBIN:       "bidv",
+		AccountNumber: "9704180000000001",
+		ServiceCode:   thaiqr.VietQRServiceCodeCard,
+	})
+	assert.Nil(t, err)
+
+	result, err := thaiqr.NewVietQR().Reader(actualPayload)
+	assert.Nil(t, err)
+	assert.Equal(t, "970418", result.BankBIN)
+	assert.Equal(t, thaiqr.VietQRServiceCodeCard, result.ServiceCode)
+	assert.Equal(t, thaiqr.POIMethodStatic, result.PointOfInitiationMethod)
+}
+
+func TestGenerateVietQRInvalid(t *testing.T) {
+	qr := thaiqr.NewVietQR()
+	_, err := qr.GeneratePayload(thaiqr.VietQRCmd{BankBIN: "999999", AccountNumber: "0011001234567"})
+	assert.Error(t, err)
+
+	_, err = qr.GeneratePayload(thaiqr.VietQRCmd{BankBIN: "970436", AccountNumber: "0011001234567", Amount: "10.5"})
+	assert.Error(t, err)
+
+	_, err = qr.GeneratePayload(thaiqr.VietQRCmd{BankBIN: "970436", AccountNumber: "0011-001"})
+	assert.Error(t, err)
+}
+
+func TestVietnamBankByBIN(t *testing.T) {
+	bank, ok := thaiqr.VietnamBankByBIN("970415")
+	assert.True(t, ok)
+	assert.Equal(t, "VietinBank", bank.ShortName)
+
+	_, ok = thaiqr.VietnamBankByBIN("000000")
+	assert.False(t, ok)
+	_, ok = thaiqr.VietnamBankByBIN("VietinBank")
+	assert.False(t, ok)
+
+	bank, ok = thaiqr.VietnamBankByShortName("vietinbank")
+	assert.True(t, ok)
+	assert.Equal(t, "970415", bank.BIN)
+	_, ok = thaiqr.VietnamBankByShortName("970415")
+	assert.False(t, ok)
+}
+
+func TestReadVietQRShortPayload(t *testing.T) {
+	for _, data := range []string{"", "ab", "abcd", "000201"} {
+		_, err := thaiqr.NewVietQR().Reader(data)
+		assert.Error(t, err, data)
+	}
+}